@@ -0,0 +1,92 @@
+// Command simctl inspects a pkg/tasks store offline, without going through
+// the lab02 backend's REST API: list tasks, show one task's metadata, or
+// summarise its checkpoint (time, shape and field length). Point it at the same --tasks-dir the backend
+// was run with.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"galaxyginiti/simulation-course/pkg/tasks"
+)
+
+func main() {
+	dir := flag.String("tasks-dir", "./tasks-data", "task store directory (must match the backend's --tasks-dir)")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	store, err := tasks.NewStore(*dir, tasks.DefaultCodec())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "simctl:", err)
+		os.Exit(1)
+	}
+
+	switch cmd := flag.Arg(0); cmd {
+	case "list":
+		runList(store)
+	case "show":
+		if flag.NArg() < 2 {
+			fmt.Fprintln(os.Stderr, "simctl: show requires a task ID")
+			os.Exit(2)
+		}
+		runShow(store, flag.Arg(1))
+	case "checkpoint":
+		if flag.NArg() < 2 {
+			fmt.Fprintln(os.Stderr, "simctl: checkpoint requires a task ID")
+			os.Exit(2)
+		}
+		runCheckpoint(store, flag.Arg(1))
+	default:
+		fmt.Fprintf(os.Stderr, "simctl: unknown command %q\n", cmd)
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: simctl [-tasks-dir dir] <list|show ID|checkpoint ID>")
+}
+
+func runList(store *tasks.Store) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tKIND\tSTATUS\tSTEPS\tUPDATED")
+	for _, t := range store.List() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", t.ID, t.Kind, t.Status, t.Steps, t.UpdatedAt.Format("2006-01-02 15:04:05"))
+	}
+	tw.Flush()
+}
+
+func runShow(store *tasks.Store, id string) {
+	t, ok := store.Get(id)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "simctl: task %q not found\n", id)
+		os.Exit(1)
+	}
+	out, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "simctl:", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(out))
+}
+
+func runCheckpoint(store *tasks.Store, id string) {
+	if !store.HasCheckpoint(id) {
+		fmt.Fprintf(os.Stderr, "simctl: task %q has no checkpoint\n", id)
+		os.Exit(1)
+	}
+	state, err := store.LoadCheckpoint(id)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "simctl:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("time=%g shape=%v fields=%d\n", state.Time, state.Shape, len(state.Field))
+}
@@ -2,17 +2,19 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"log"
-	"math"
 	"net/http"
+	"time"
+
+	"galaxyginiti/simulation-course/pkg/ballistics"
+	"galaxyginiti/simulation-course/pkg/metrics"
 )
 
 const (
-	g     = 9.81  // ускорение свободного падения, м/с²
-	rho   = 1.225 // плотность воздуха, кг/м³
-	Cd    = 0.47  // коэффициент аэродинамического сопротивления (сфера)
-	mass  = 1.0   // масса тела, кг
-	area  = 0.01  // площадь поперечного сечения, м²
+	Cd   = 0.47 // коэффициент аэродинамического сопротивления (сфера)
+	mass = 1.0  // масса тела, кг
+	area = 0.01 // площадь поперечного сечения, м²
 )
 
 type Point struct {
@@ -26,7 +28,7 @@ type SimulationRequest struct {
 	V0    float64 `json:"v0"`    // начальная скорость, м/с
 	Angle float64 `json:"angle"` // угол к горизонту, градусы
 	H0    float64 `json:"h0"`    // начальная высота, м
-	Dt    float64 `json:"dt"`    // шаг моделирования, с
+	Dt    float64 `json:"dt"`    // шаг моделирования, с (верхняя граница адаптивного шага)
 }
 
 type SimulationResponse struct {
@@ -38,48 +40,40 @@ type SimulationResponse struct {
 	SimulationSteps int     `json:"simulationSteps"` // количество шагов
 }
 
+// planarParams is the point-mass-with-drag model the 2D endpoint has
+// always used, now expressed as a ballistics.Params with no wind and no
+// spin. Previously this logic was an inline forward-Euler loop in simulate
+// using a fixed-rho drag force; it now
+// runs through pkg/ballistics (ISA air density, adaptive RKF45) like the
+// 3D endpoint in projectile3d.go, projected into the zero-wind,
+// zero-spin plane so the two endpoints share one force model.
+var planarParams = ballistics.Params{Mass: mass, Area: area, Cd: Cd}
+
 func simulate(req SimulationRequest) SimulationResponse {
-	// Начальные условия
-	angleRad := req.Angle * math.Pi / 180
-	vx := req.V0 * math.Cos(angleRad)
-	vy := req.V0 * math.Sin(angleRad)
-	x := 0.0
-	y := req.H0
+	integrator := ballistics.NewRKF45(ballistics.NewDerivative(planarParams))
+	integrator.MaxStep = req.Dt
+
+	state := ballistics.PlanarState(req.V0, req.Angle, req.H0).Vector()
 	t := 0.0
+	dt := req.Dt
 
 	trajectory := []Point{}
-	maxHeight := y
+	maxHeight := state[2]
 	steps := 0
 
 	// Моделирование полёта
-	for y >= 0 {
+	for state[2] >= 0 {
 		// Сохраняем текущую точку
-		v := math.Sqrt(vx*vx + vy*vy)
-		trajectory = append(trajectory, Point{X: x, Y: y, V: v, T: t})
+		trajectory = append(trajectory, Point{X: state[0], Y: state[2], V: ballistics.StateFromVector(state).Speed(), T: t})
 
-		if y > maxHeight {
-			maxHeight = y
+		if state[2] > maxHeight {
+			maxHeight = state[2]
 		}
 
-		// Расчёт силы сопротивления воздуха
-		dragForce := 0.5 * rho * Cd * area * v * v
-
-		// Ускорения с учётом силы сопротивления
-		ax := -(dragForce / mass) * (vx / v)
-		ay := -g - (dragForce / mass) * (vy / v)
-
-		// Обработка случая нулевой скорости
-		if v == 0 {
-			ax = 0
-			ay = -g
-		}
-
-		// Обновление скоростей и координат методом Эйлера
-		vx += ax * req.Dt
-		vy += ay * req.Dt
-		x += vx * req.Dt
-		y += vy * req.Dt
-		t += req.Dt
+		var dtUsed, dtNext float64
+		state, dtUsed, dtNext = integrator.Step(t, state, dt)
+		t += dtUsed
+		dt = dtNext
 
 		steps++
 
@@ -90,11 +84,11 @@ func simulate(req SimulationRequest) SimulationResponse {
 	}
 
 	// Финальная точка (на земле)
-	finalV := math.Sqrt(vx*vx + vy*vy)
+	finalV := ballistics.StateFromVector(state).Speed()
 
 	return SimulationResponse{
 		Trajectory:      trajectory,
-		Range:           x,
+		Range:           state[0],
 		MaxHeight:       maxHeight,
 		FinalVelocity:   finalV,
 		TimeOfFlight:    t,
@@ -127,16 +121,33 @@ func simulateHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Валидация входных данных
 	if req.V0 <= 0 || req.Angle < 0 || req.Angle > 90 || req.Dt <= 0 {
+		metrics.RequestsTotal.WithLabelValues("projectile", "invalid").Inc()
 		http.Error(w, "Invalid parameters", http.StatusBadRequest)
 		return
 	}
 
+	start := time.Now()
 	result := simulate(req)
+	metrics.DurationSeconds.WithLabelValues("projectile").Observe(time.Since(start).Seconds())
+	metrics.StepsTotal.WithLabelValues("projectile").Add(float64(result.SimulationSteps))
+	metrics.TrajectorySteps.Observe(float64(result.SimulationSteps))
+	metrics.RequestsTotal.WithLabelValues("projectile", "success").Inc()
+
 	json.NewEncoder(w).Encode(result)
 }
 
+var metricsAddr = flag.String("metrics-addr", ":9101", "address to serve Prometheus metrics on (kept off the public :8080 listener)")
+
 func main() {
+	flag.Parse()
+
+	go func() {
+		log.Printf("Metrics server starting on %s", *metricsAddr)
+		log.Fatal(metrics.Serve(*metricsAddr))
+	}()
+
 	http.HandleFunc("/api/simulate", simulateHandler)
+	http.HandleFunc("/api/simulate3d", simulate3DHandler)
 
 	log.Println("Server started on http://localhost:8080")
 	log.Fatal(http.ListenAndServe(":8080", nil))
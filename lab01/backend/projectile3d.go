@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"time"
+
+	"galaxyginiti/simulation-course/pkg/ballistics"
+	"galaxyginiti/simulation-course/pkg/metrics"
+)
+
+// WindSpec selects and configures one of pkg/ballistics's WindField
+// implementations over the wire. Type selects which of the other fields
+// apply: "constant" (Vx/Vy/Vz), "layered" (Layers) or "grid" (Grid).
+type WindSpec struct {
+	Type string `json:"type"`
+
+	Vx float64 `json:"vx,omitempty"`
+	Vy float64 `json:"vy,omitempty"`
+	Vz float64 `json:"vz,omitempty"`
+
+	Layers []ballistics.WindLayer `json:"layers,omitempty"`
+
+	Grid *struct {
+		Xs []float64   `json:"xs"`
+		Ys []float64   `json:"ys"`
+		Vx [][]float64 `json:"vx"`
+		Vy [][]float64 `json:"vy"`
+		Vz [][]float64 `json:"vz"`
+	} `json:"grid,omitempty"`
+}
+
+func (w *WindSpec) field() ballistics.WindField {
+	if w == nil {
+		return ballistics.ZeroWind{}
+	}
+	switch w.Type {
+	case "constant":
+		return ballistics.ConstantWind{Vx: w.Vx, Vy: w.Vy, Vz: w.Vz}
+	case "layered":
+		return ballistics.LayeredWind{Layers: w.Layers}
+	case "grid":
+		if w.Grid == nil {
+			return ballistics.ZeroWind{}
+		}
+		return ballistics.GridWind{Xs: w.Grid.Xs, Ys: w.Grid.Ys, Vx: w.Grid.Vx, Vy: w.Grid.Vy, Vz: w.Grid.Vz}
+	default:
+		return ballistics.ZeroWind{}
+	}
+}
+
+// Simulation3DRequest is the full 3-DOF counterpart to SimulationRequest:
+// it adds crossrange (Azimuth), spin, an optional wind field, Magnus lift
+// and an optional Coriolis term.
+type Simulation3DRequest struct {
+	V0      float64 `json:"v0"`      // начальная скорость, м/с
+	Angle   float64 `json:"angle"`   // угол к горизонту, градусы
+	Azimuth float64 `json:"azimuth"` // азимут (в горизонтальной плоскости), градусы
+	H0      float64 `json:"h0"`      // начальная высота, м
+	Dt      float64 `json:"dt"`      // шаг моделирования, с (верхняя граница адаптивного шага)
+
+	Mass float64 `json:"mass"` // масса тела, кг; 0 uses the 2D endpoint's default
+	Area float64 `json:"area"` // площадь поперечного сечения, м²; 0 uses the default
+	Cd   float64 `json:"cd"`   // коэффициент лобового сопротивления; 0 uses the default
+	Cl   float64 `json:"cl"`   // коэффициент эффекта Магнуса; 0 disables it
+
+	// Spin is the initial angular velocity (rad/s) about each axis.
+	Spin [3]float64 `json:"spin"`
+
+	Wind *WindSpec `json:"wind,omitempty"`
+
+	// Coriolis, if set, is the planet's angular velocity vector (rad/s);
+	// omitted disables the Coriolis term.
+	Coriolis *[3]float64 `json:"coriolis,omitempty"`
+}
+
+// Point3D is a single reported frame of a 3-DOF trajectory.
+type Point3D struct {
+	X float64 `json:"x"` // дальность, м
+	Y float64 `json:"y"` // боковое отклонение, м
+	Z float64 `json:"z"` // высота, м
+	V float64 `json:"v"` // скорость, м/с
+	T float64 `json:"t"` // время, с
+}
+
+// Simulation3DResponse mirrors SimulationResponse for a 3-DOF run.
+type Simulation3DResponse struct {
+	Trajectory      []Point3D `json:"trajectory"`
+	Range           float64   `json:"range"`           // дальность по x, м
+	Deflection      float64   `json:"deflection"`      // боковое отклонение по y, м
+	MaxHeight       float64   `json:"maxHeight"`       // максимальная высота, м
+	FinalVelocity   float64   `json:"finalVelocity"`   // скорость в конечной точке, м/с
+	TimeOfFlight    float64   `json:"timeOfFlight"`    // время полёта, с
+	SimulationSteps int       `json:"simulationSteps"` // количество шагов
+}
+
+func (req Simulation3DRequest) params() ballistics.Params {
+	p := ballistics.Params{
+		Mass: req.Mass,
+		Area: req.Area,
+		Cd:   req.Cd,
+		Cl:   req.Cl,
+		Wind: req.Wind.field(),
+	}
+	if p.Mass == 0 {
+		p.Mass = mass
+	}
+	if p.Area == 0 {
+		p.Area = area
+	}
+	if p.Cd == 0 {
+		p.Cd = Cd
+	}
+	if req.Coriolis != nil {
+		p.Coriolis = req.Coriolis
+	}
+	return p
+}
+
+func simulate3D(req Simulation3DRequest) Simulation3DResponse {
+	angle := req.Angle * math.Pi / 180
+	azimuth := req.Azimuth * math.Pi / 180
+	horizontal := req.V0 * math.Cos(angle)
+
+	initial := ballistics.State{
+		Z:  req.H0,
+		Vx: horizontal * math.Cos(azimuth),
+		Vy: horizontal * math.Sin(azimuth),
+		Vz: req.V0 * math.Sin(angle),
+		Wx: req.Spin[0], Wy: req.Spin[1], Wz: req.Spin[2],
+	}
+
+	integrator := ballistics.NewRKF45(ballistics.NewDerivative(req.params()))
+	integrator.MaxStep = req.Dt
+
+	state := initial.Vector()
+	t, dt := 0.0, req.Dt
+	trajectory := []Point3D{}
+	maxHeight := state[2]
+	steps := 0
+
+	for state[2] >= 0 {
+		s := ballistics.StateFromVector(state)
+		trajectory = append(trajectory, Point3D{X: s.X, Y: s.Y, Z: s.Z, V: s.Speed(), T: t})
+		if s.Z > maxHeight {
+			maxHeight = s.Z
+		}
+
+		var dtUsed, dtNext float64
+		state, dtUsed, dtNext = integrator.Step(t, state, dt)
+		t += dtUsed
+		dt = dtNext
+
+		steps++
+		if steps > 1000000 {
+			break
+		}
+	}
+
+	final := ballistics.StateFromVector(state)
+	return Simulation3DResponse{
+		Trajectory:      trajectory,
+		Range:           final.X,
+		Deflection:      final.Y,
+		MaxHeight:       maxHeight,
+		FinalVelocity:   final.Speed(),
+		TimeOfFlight:    t,
+		SimulationSteps: steps,
+	}
+}
+
+func simulate3DHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req Simulation3DRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.V0 <= 0 || req.Angle < -90 || req.Angle > 90 || req.Dt <= 0 {
+		metrics.RequestsTotal.WithLabelValues("projectile3d", "invalid").Inc()
+		http.Error(w, "Invalid parameters", http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now()
+	result := simulate3D(req)
+	metrics.DurationSeconds.WithLabelValues("projectile3d").Observe(time.Since(start).Seconds())
+	metrics.StepsTotal.WithLabelValues("projectile3d").Add(float64(result.SimulationSteps))
+	metrics.TrajectorySteps.Observe(float64(result.SimulationSteps))
+	metrics.RequestsTotal.WithLabelValues("projectile3d", "success").Inc()
+
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"math"
+
+	"galaxyginiti/simulation-course/pkg/metrics"
+	"galaxyginiti/simulation-course/pkg/tasks"
+)
+
+// Region overrides the material (and so the local thermal diffusivity)
+// inside an axis-aligned box of the plate. Boxes are tested in order and
+// the first one containing a cell wins; cells in no region use the
+// background material derived from SimulationParams.Alpha.
+type Region struct {
+	X0 float64 `json:"x0"`
+	Y0 float64 `json:"y0"`
+	X1 float64 `json:"x1"`
+	Y1 float64 `json:"y1"`
+
+	K   float64 `json:"k"`   // thermal conductivity, W/(m·K)
+	Rho float64 `json:"rho"` // density, kg/m³
+	C   float64 `json:"c"`   // specific heat capacity, J/(kg·K)
+}
+
+func (r Region) contains(x, y float64) bool {
+	return x >= r.X0 && x <= r.X1 && y >= r.Y0 && y <= r.Y1
+}
+
+func (r Region) alpha() float64 { return r.K / (r.Rho * r.C) }
+
+// HeatSource is a constant volumetric heat generation rate Q (W/m³) active
+// inside an axis-aligned box for a window of simulated time. EndTime <= 0
+// means "stays on forever once StartTime is reached".
+type HeatSource struct {
+	X0 float64 `json:"x0"`
+	Y0 float64 `json:"y0"`
+	X1 float64 `json:"x1"`
+	Y1 float64 `json:"y1"`
+
+	Q         float64 `json:"q"`
+	StartTime float64 `json:"startTime"`
+	EndTime   float64 `json:"endTime"`
+}
+
+func (s HeatSource) contains(x, y float64) bool {
+	return x >= s.X0 && x <= s.X1 && y >= s.Y0 && y <= s.Y1
+}
+
+func (s HeatSource) activeAt(t float64) bool {
+	return t >= s.StartTime && (s.EndTime <= 0 || t <= s.EndTime)
+}
+
+// BoundaryCondition is one edge's condition: Dirichlet fixes the
+// temperature, Neumann fixes the inward heat flux (W/m²), and Robin
+// applies convective exchange flux = H*(Tinf - T) with the environment.
+type BoundaryCondition struct {
+	Type  string  `json:"type"` // "dirichlet" | "neumann" | "robin"
+	Value float64 `json:"value"`
+	H     float64 `json:"h"`
+	TInf  float64 `json:"tInf"`
+}
+
+// Boundaries carries one BoundaryCondition per edge of the plate.
+type Boundaries struct {
+	Top    BoundaryCondition `json:"top"`
+	Bottom BoundaryCondition `json:"bottom"`
+	Left   BoundaryCondition `json:"left"`
+	Right  BoundaryCondition `json:"right"`
+}
+
+// Background material used where no Region covers a cell. Matches the
+// aluminum default used by the 1D rod (see withDefaults), so a plain
+// params.Alpha still means "aluminum-like" in 2D.
+const (
+	bgK   = 237.0
+	bgRho = 2700.0
+	bgC   = 900.0
+)
+
+// grid2D is the per-cell material map for a 2D plate, flattened
+// row-major: cell (i, j) lives at index j*nx+i.
+type grid2D struct {
+	nx, ny int
+	dx, dy float64
+	alpha  []float64
+	k      []float64
+}
+
+func newGrid2D(params SimulationParams) *grid2D {
+	nx, ny := params.Nx, params.Ny
+	g := &grid2D{
+		nx:    nx,
+		ny:    ny,
+		dx:    params.Width / float64(nx-1),
+		dy:    params.Height / float64(ny-1),
+		alpha: make([]float64, nx*ny),
+		k:     make([]float64, nx*ny),
+	}
+
+	bgAlpha := params.Alpha
+	if bgAlpha == 0 {
+		bgAlpha = bgK / (bgRho * bgC)
+	}
+	bgKLocal := bgAlpha * bgRho * bgC
+
+	for j := 0; j < ny; j++ {
+		y := float64(j) * g.dy
+		for i := 0; i < nx; i++ {
+			x := float64(i) * g.dx
+			idx := j*nx + i
+
+			alpha, k := bgAlpha, bgKLocal
+			for _, region := range params.Regions {
+				if region.contains(x, y) {
+					alpha, k = region.alpha(), region.K
+					break
+				}
+			}
+			g.alpha[idx] = alpha
+			g.k[idx] = k
+		}
+	}
+
+	return g
+}
+
+func (g *grid2D) idx(i, j int) int { return j*g.nx + i }
+
+// maxAlpha returns the largest diffusivity on the grid, which governs the
+// 2D CFL limit alpha*dt*(1/dx^2 + 1/dy^2) <= 0.5.
+func (g *grid2D) maxAlpha() float64 {
+	max := 0.0
+	for _, a := range g.alpha {
+		if a > max {
+			max = a
+		}
+	}
+	return max
+}
+
+func cfl2D(alpha, dt, dx, dy float64) float64 {
+	return alpha * dt * (1/(dx*dx) + 1/(dy*dy))
+}
+
+// heat2D is the stepper for a 2D rectangular plate with per-region
+// material and mixed (Dirichlet/Neumann/Robin) boundary conditions,
+// integrated with the explicit 5-point Laplacian stencil.
+type heat2D struct {
+	params     SimulationParams
+	grid       *grid2D
+	boundaries Boundaries
+	sources    []HeatSource
+
+	T           []float64
+	currentTime float64
+	dt          float64
+	adaptedDt   float64
+
+	step        int
+	totalSteps  int
+	reportEvery int
+}
+
+func newHeat2D(params SimulationParams) *heat2D {
+	h := &heat2D{}
+	h.reconfigure(params)
+
+	T := make([]float64, params.Nx*params.Ny)
+	for i := range T {
+		T[i] = params.InitialTemp
+	}
+	h.T = T
+	h.applyDirichlet()
+	return h
+}
+
+func (h *heat2D) shape() []int { return []int{h.grid.nx, h.grid.ny} }
+
+func (h *heat2D) resumeFrom(state tasks.CheckpointState, stepsDone int) {
+	h.T = append([]float64{}, state.Field...)
+	h.currentTime = state.Time
+	h.step = stepsDone
+}
+
+// reconfigure rebuilds the material grid, boundary conditions, sources and
+// time step for new parameters, without resetting the temperature field or
+// elapsed time. Grid size (Width/Height/Nx/Ny) cannot be changed this way.
+func (h *heat2D) reconfigure(params SimulationParams) {
+	grid := newGrid2D(params)
+
+	dt := params.TimeStep
+	adaptedDt := 0.0
+	r := cfl2D(grid.maxAlpha(), dt, grid.dx, grid.dy)
+	metrics.CFLRatio.Set(r)
+	if r > 0.5 {
+		metrics.UnstableRejectionsTotal.WithLabelValues("heat").Inc()
+		for cfl2D(grid.maxAlpha(), dt, grid.dx, grid.dy) > 0.5 {
+			dt /= 2
+		}
+		adaptedDt = dt
+	}
+
+	boundaries := Boundaries{}
+	if params.Boundaries != nil {
+		boundaries = *params.Boundaries
+	}
+
+	h.params = params
+	h.grid = grid
+	h.boundaries = boundaries
+	h.sources = params.Sources
+	h.dt = dt
+	h.adaptedDt = adaptedDt
+	h.totalSteps = int(params.TotalTime / dt)
+	h.reportEvery = int(math.Max(1, math.Round(10*params.TimeStep/dt)))
+
+	if h.T != nil {
+		h.applyDirichlet()
+	}
+}
+
+// applyDirichlet pins every edge cell whose boundary condition is
+// Dirichlet to its fixed value; Neumann/Robin edges are left for the
+// stencil to update via ghost values.
+func (h *heat2D) applyDirichlet() {
+	nx, ny := h.grid.nx, h.grid.ny
+	if h.boundaries.Left.Type == "dirichlet" {
+		for j := 0; j < ny; j++ {
+			h.T[h.grid.idx(0, j)] = h.boundaries.Left.Value
+		}
+	}
+	if h.boundaries.Right.Type == "dirichlet" {
+		for j := 0; j < ny; j++ {
+			h.T[h.grid.idx(nx-1, j)] = h.boundaries.Right.Value
+		}
+	}
+	if h.boundaries.Bottom.Type == "dirichlet" {
+		for i := 0; i < nx; i++ {
+			h.T[h.grid.idx(i, 0)] = h.boundaries.Bottom.Value
+		}
+	}
+	if h.boundaries.Top.Type == "dirichlet" {
+		for i := 0; i < nx; i++ {
+			h.T[h.grid.idx(i, ny-1)] = h.boundaries.Top.Value
+		}
+	}
+}
+
+// ghostFlux returns the inward heat flux (W/m²) to use at a boundary cell
+// with conductivity k and current temperature t, for a non-Dirichlet
+// boundary condition.
+func ghostFlux(bc BoundaryCondition, k, t float64) float64 {
+	if bc.Type == "robin" {
+		return bc.H * (bc.TInf - t)
+	}
+	return bc.Value // neumann: flux is given directly
+}
+
+// ghost returns the temperature of the fictitious node just outside edge,
+// at spacing d beyond the boundary node, with neighbor the interior node
+// one spacing in on the opposite side of the boundary node from the ghost
+// (so ghost and neighbor are symmetric about it, 2d apart). Let n be the
+// outward normal and flux be positive when heat flows *into* the domain,
+// i.e. flux = -k*dT/dn_in where n_in = -n is the inward normal, which is
+// flux = k*dT/dn. Approximating that derivative with the centered
+// difference (ghost-neighbor)/(2d) and solving for ghost gives
+// ghost = neighbor + 2*d*flux/k. Because ghost and neighbor's roles swap
+// with n itself at each edge, this same sign holds on all four edges.
+func ghost(bc BoundaryCondition, k, d, neighbor, boundaryTemp float64) float64 {
+	flux := ghostFlux(bc, k, boundaryTemp)
+	return neighbor + 2*d*flux/k
+}
+
+func (h *heat2D) neighborValue(i, j, di, dj int, bc BoundaryCondition, boundaryTemp float64) float64 {
+	ni, nj := i+di, j+dj
+	if ni < 0 || ni >= h.grid.nx || nj < 0 || nj >= h.grid.ny {
+		d := h.grid.dx
+		if dj != 0 {
+			d = h.grid.dy
+		}
+		return ghost(bc, h.grid.k[h.grid.idx(i, j)], d, h.T[h.grid.idx(i-di, j-dj)], boundaryTemp)
+	}
+	return h.T[h.grid.idx(ni, nj)]
+}
+
+func (h *heat2D) sourceTerm(i, j int, x, y float64) float64 {
+	idx := h.grid.idx(i, j)
+	q := 0.0
+	for _, src := range h.sources {
+		if src.activeAt(h.currentTime) && src.contains(x, y) {
+			q += src.Q
+		}
+	}
+	if q == 0 {
+		return 0
+	}
+	// Q is volumetric (W/m³); convert to a temperature rate using the
+	// same rho*c implied by this cell's k and alpha (k = alpha*rho*c).
+	rhoC := h.grid.k[idx] / h.grid.alpha[idx]
+	return q / rhoC
+}
+
+func (h *heat2D) stepOnce() {
+	nx, ny := h.grid.nx, h.grid.ny
+	next := make([]float64, len(h.T))
+	dx2, dy2 := h.grid.dx*h.grid.dx, h.grid.dy*h.grid.dy
+
+	for j := 0; j < ny; j++ {
+		y := float64(j) * h.grid.dy
+		for i := 0; i < nx; i++ {
+			x := float64(i) * h.grid.dx
+			idx := h.grid.idx(i, j)
+
+			if i == 0 && h.boundaries.Left.Type == "dirichlet" ||
+				i == nx-1 && h.boundaries.Right.Type == "dirichlet" ||
+				j == 0 && h.boundaries.Bottom.Type == "dirichlet" ||
+				j == ny-1 && h.boundaries.Top.Type == "dirichlet" {
+				next[idx] = h.T[idx]
+				continue
+			}
+
+			left := h.neighborValue(i, j, -1, 0, h.boundaries.Left, h.T[idx])
+			right := h.neighborValue(i, j, 1, 0, h.boundaries.Right, h.T[idx])
+			bottom := h.neighborValue(i, j, 0, -1, h.boundaries.Bottom, h.T[idx])
+			top := h.neighborValue(i, j, 0, 1, h.boundaries.Top, h.T[idx])
+
+			laplacian := (right-2*h.T[idx]+left)/dx2 + (top-2*h.T[idx]+bottom)/dy2
+			next[idx] = h.T[idx] + h.dt*(h.grid.alpha[idx]*laplacian+h.sourceTerm(i, j, x, y))
+		}
+	}
+
+	h.T = next
+	h.currentTime += h.dt
+	metrics.StepsTotal.WithLabelValues("heat").Inc()
+}
+
+func (h *heat2D) result() SimulationResult {
+	center := h.grid.idx(h.grid.nx/2, h.grid.ny/2)
+	return SimulationResult{
+		Temperatures:    append([]float64{}, h.T...),
+		Time:            h.currentTime,
+		CenterTemp:      h.T[center],
+		Stable:          true,
+		Method:          "explicit-ftcs-2d",
+		AdaptedTimeStep: h.adaptedDt,
+	}
+}
+
+func (h *heat2D) advance() (SimulationResult, bool) {
+	for h.step < h.totalSteps {
+		h.stepOnce()
+		report := h.step%h.reportEvery == 0 || h.step == h.totalSteps-1
+		h.step++
+		if report {
+			return h.result(), h.step < h.totalSteps
+		}
+	}
+	return h.result(), false
+}
@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func newTestPlate(boundaries Boundaries) *heat2D {
+	params := SimulationParams{
+		Width:       1.0,
+		Height:      1.0,
+		Nx:          3,
+		Ny:          3,
+		TimeStep:    0.01,
+		TotalTime:   1.0,
+		InitialTemp: 0,
+		Alpha:       1e-4,
+		Boundaries:  &boundaries,
+	}
+	return newHeat2D(params)
+}
+
+// TestNeumannInwardFluxHeatsBoundary checks that a positive (inward)
+// Neumann flux on the left edge raises the boundary cell's temperature
+// above its initial value, not below it.
+func TestNeumannInwardFluxHeatsBoundary(t *testing.T) {
+	h := newTestPlate(Boundaries{
+		Left: BoundaryCondition{Type: "neumann", Value: 1000},
+	})
+
+	for i := 0; i < 20; i++ {
+		h.stepOnce()
+	}
+
+	got := h.T[h.grid.idx(0, 1)]
+	if got <= 0 {
+		t.Errorf("left-edge temperature = %v, want > 0 (inward flux should heat the boundary)", got)
+	}
+}
+
+// TestRobinBoundaryWarmsTowardAmbient checks that a Robin edge with an
+// ambient temperature hotter than the plate warms the boundary cell
+// toward TInf, rather than cooling it further.
+func TestRobinBoundaryWarmsTowardAmbient(t *testing.T) {
+	h := newTestPlate(Boundaries{
+		Left: BoundaryCondition{Type: "robin", H: 50, TInf: 1000},
+	})
+
+	for i := 0; i < 20; i++ {
+		h.stepOnce()
+	}
+
+	got := h.T[h.grid.idx(0, 1)]
+	if got <= 0 {
+		t.Errorf("left-edge temperature = %v, want > 0 (edge should warm toward a hotter ambient)", got)
+	}
+}
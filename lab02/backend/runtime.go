@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"galaxyginiti/simulation-course/pkg/metrics"
+	"galaxyginiti/simulation-course/pkg/tasks"
+)
+
+// errAlreadyRunning is returned by resumeTask when the task it was asked
+// to resume already has a live run registered.
+var errAlreadyRunning = errors.New("tasks: task is already running")
+
+// runningTask tracks one simulation executing in the background, so a
+// websocket (or several) can attach to it as a live view without owning
+// its lifecycle: the run keeps going, checkpointing and streaming to its
+// artifact, independent of whether anyone is watching.
+type runningTask struct {
+	id      string
+	cancel  context.CancelFunc
+	control chan clientMessage
+
+	mu   sync.Mutex
+	subs map[chan SimulationResult]struct{}
+}
+
+func newRunningTask(id string, cancel context.CancelFunc) *runningTask {
+	return &runningTask{
+		id:      id,
+		cancel:  cancel,
+		control: make(chan clientMessage, 4),
+		subs:    map[chan SimulationResult]struct{}{},
+	}
+}
+
+// subscribe registers a live-view channel for this task's frames.
+// Unsubscribe with unsubscribe once the viewer disconnects.
+func (rt *runningTask) subscribe() chan SimulationResult {
+	ch := make(chan SimulationResult, defaultBufferSize)
+	rt.mu.Lock()
+	rt.subs[ch] = struct{}{}
+	rt.mu.Unlock()
+	return ch
+}
+
+func (rt *runningTask) unsubscribe(ch chan SimulationResult) {
+	rt.mu.Lock()
+	delete(rt.subs, ch)
+	rt.mu.Unlock()
+	close(ch)
+}
+
+// broadcast fans result out to every live subscriber. It never blocks the
+// simulation: a subscriber too slow to keep up just misses frames.
+func (rt *runningTask) broadcast(result SimulationResult) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	for ch := range rt.subs {
+		select {
+		case ch <- result:
+		default:
+		}
+	}
+}
+
+var (
+	runningMu    sync.Mutex
+	runningTasks = map[string]*runningTask{}
+)
+
+func registerRunning(rt *runningTask) {
+	runningMu.Lock()
+	runningTasks[rt.id] = rt
+	runningMu.Unlock()
+}
+
+func unregisterRunning(id string) {
+	runningMu.Lock()
+	delete(runningTasks, id)
+	runningMu.Unlock()
+}
+
+func lookupRunning(id string) (*runningTask, bool) {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	rt, ok := runningTasks[id]
+	return rt, ok
+}
+
+// tryRegisterRunning registers rt unless a run for rt.id is already
+// registered, reporting whether it won the race. Checking lookupRunning
+// and then registerRunning separately would leave a window where two
+// concurrent resumes of the same task both see it as not-running.
+func tryRegisterRunning(rt *runningTask) bool {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	if _, exists := runningTasks[rt.id]; exists {
+		return false
+	}
+	runningTasks[rt.id] = rt
+	return true
+}
+
+// startTask persists a new task and launches its background run, detached
+// from any one caller: an HTTP request returns as soon as the task is
+// created, and a websocket that started it can disconnect without killing
+// it. It returns as soon as the run is launched; the run itself ends once
+// it finishes, is stopped, or fails.
+func startTask(kind string, params SimulationParams) (*tasks.Task, *runningTask, error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	task, err := taskStore.Create(kind, raw, checkpointEvery, compressionLevel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rt := newRunningTask(task.ID, cancel)
+	registerRunning(rt)
+
+	metrics.RequestsTotal.WithLabelValues(kind, "success").Inc()
+	start := time.Now()
+	go func() {
+		defer unregisterRunning(task.ID)
+		defer func() { metrics.DurationSeconds.WithLabelValues(kind).Observe(time.Since(start).Seconds()) }()
+		runPersistentTask(ctx, task.ID, withDefaults(params), rt.control, rt.broadcast)
+	}()
+
+	return task, rt, nil
+}
+
+func toCheckpointState(result SimulationResult, shape []int) tasks.CheckpointState {
+	return tasks.CheckpointState{Time: result.Time, Shape: shape, Field: result.Temperatures}
+}
+
+// runPersistentTask drives sim to completion (or until ctx is cancelled),
+// checkpointing every checkpointEvery steps and streaming every frame to
+// its artifact writer as it's produced, so the full trajectory never has
+// to be held in memory. onFrame additionally publishes each frame to any
+// live viewers; control carries pause/resume/updateParams requests from
+// whichever viewer currently controls the run.
+func runPersistentTask(ctx context.Context, id string, params SimulationParams, control <-chan clientMessage, onFrame func(SimulationResult)) {
+	taskStore.SetStatus(id, tasks.StatusRunning, "")
+
+	writer, err := taskStore.OpenArtifact(id)
+	if err != nil {
+		taskStore.SetStatus(id, tasks.StatusFailed, err.Error())
+		return
+	}
+
+	sim := newStepper(params)
+	step := 0
+	finished := false
+	defer func() {
+		if finished {
+			if err := writer.Close(); err != nil {
+				taskStore.SetStatus(id, tasks.StatusFailed, err.Error())
+				return
+			}
+			taskStore.FinishArtifact(id)
+			return
+		}
+		writer.Abort()
+	}()
+
+	record := func(result SimulationResult) {
+		state := toCheckpointState(result, sim.shape())
+		writer.WriteFrame(state)
+		onFrame(result)
+		step++
+		if checkpointEvery > 0 && step%checkpointEvery == 0 {
+			taskStore.SaveCheckpoint(id, step, state)
+		}
+	}
+	record(sim.result())
+
+	paused := false
+	for {
+		if paused {
+			select {
+			case msg, ok := <-control:
+				if !ok {
+					return
+				}
+				if msg.Type == "resume" {
+					paused = false
+				} else if msg.Type == "updateParams" && msg.Params != nil {
+					sim.reconfigure(withDefaults(*msg.Params))
+				}
+			case <-ctx.Done():
+				taskStore.SetStatus(id, tasks.StatusFailed, "stopped")
+				return
+			}
+			continue
+		}
+
+		select {
+		case msg, ok := <-control:
+			if !ok {
+				return
+			}
+			switch msg.Type {
+			case "pause":
+				paused = true
+			case "updateParams":
+				if msg.Params != nil {
+					sim.reconfigure(withDefaults(*msg.Params))
+				}
+			}
+			continue
+		case <-ctx.Done():
+			taskStore.SetStatus(id, tasks.StatusFailed, "stopped")
+			return
+		default:
+		}
+
+		result, more := sim.advance()
+		record(result)
+		if !more {
+			finished = true
+			return
+		}
+	}
+}
+
+// resumeTask rehydrates a stopped task's stepper from its last checkpoint
+// and continues the run from there, appending to a fresh artifact (the
+// checkpoint's frame becomes the new artifact's first frame).
+func resumeTask(id string) (*runningTask, error) {
+	task, ok := taskStore.Get(id)
+	if !ok {
+		return nil, errUnknownTask
+	}
+	checkpoint, err := taskStore.LoadCheckpoint(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var params SimulationParams
+	if err := json.Unmarshal(task.Params, &params); err != nil {
+		return nil, err
+	}
+	params = withDefaults(params)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rt := newRunningTask(task.ID, cancel)
+	if !tryRegisterRunning(rt) {
+		cancel()
+		return nil, errAlreadyRunning
+	}
+
+	metrics.RequestsTotal.WithLabelValues(task.Kind, "resumed").Inc()
+	start := time.Now()
+	go func() {
+		defer unregisterRunning(task.ID)
+		defer func() { metrics.DurationSeconds.WithLabelValues(task.Kind).Observe(time.Since(start).Seconds()) }()
+		runResumedTask(ctx, task.ID, params, checkpoint, task.Steps, rt.control, rt.broadcast)
+	}()
+
+	return rt, nil
+}
+
+func runResumedTask(ctx context.Context, id string, params SimulationParams, checkpoint tasks.CheckpointState, stepsDone int, control <-chan clientMessage, onFrame func(SimulationResult)) {
+	taskStore.SetStatus(id, tasks.StatusRunning, "")
+
+	writer, err := taskStore.OpenArtifact(id)
+	if err != nil {
+		taskStore.SetStatus(id, tasks.StatusFailed, err.Error())
+		return
+	}
+
+	sim := newStepper(params)
+	sim.resumeFrom(checkpoint, stepsDone)
+	step := stepsDone
+	finished := false
+	defer func() {
+		if finished {
+			if err := writer.Close(); err != nil {
+				taskStore.SetStatus(id, tasks.StatusFailed, err.Error())
+				return
+			}
+			taskStore.FinishArtifact(id)
+			return
+		}
+		writer.Abort()
+	}()
+
+	record := func(result SimulationResult) {
+		state := toCheckpointState(result, sim.shape())
+		writer.WriteFrame(state)
+		onFrame(result)
+		step++
+		if checkpointEvery > 0 && step%checkpointEvery == 0 {
+			taskStore.SaveCheckpoint(id, step, state)
+		}
+	}
+	record(sim.result())
+
+	for {
+		select {
+		case msg, ok := <-control:
+			if !ok {
+				return
+			}
+			if msg.Type == "updateParams" && msg.Params != nil {
+				sim.reconfigure(withDefaults(*msg.Params))
+			}
+			continue
+		case <-ctx.Done():
+			taskStore.SetStatus(id, tasks.StatusFailed, "stopped")
+			return
+		default:
+		}
+
+		result, more := sim.advance()
+		record(result)
+		if !more {
+			finished = true
+			return
+		}
+	}
+}
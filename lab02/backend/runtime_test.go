@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// TestTryRegisterRunningRejectsDuplicate guards against the resume race
+// fixed alongside this test: two concurrent attempts to register a run
+// for the same task id must not both succeed.
+func TestTryRegisterRunningRejectsDuplicate(t *testing.T) {
+	id := "test-task-dup"
+	defer unregisterRunning(id)
+
+	_, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	rt1 := newRunningTask(id, cancel1)
+	if !tryRegisterRunning(rt1) {
+		t.Fatal("first registration should succeed")
+	}
+
+	_, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	rt2 := newRunningTask(id, cancel2)
+	if tryRegisterRunning(rt2) {
+		t.Fatal("second registration for the same id should be rejected")
+	}
+
+	got, ok := lookupRunning(id)
+	if !ok || got != rt1 {
+		t.Fatal("registry should still hold the first runningTask")
+	}
+}
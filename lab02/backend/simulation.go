@@ -0,0 +1,237 @@
+package main
+
+import (
+	"math"
+
+	"galaxyginiti/simulation-course/pkg/metrics"
+	"galaxyginiti/simulation-course/pkg/solver"
+	"galaxyginiti/simulation-course/pkg/tasks"
+)
+
+// SimulationParams contains parameters for heat conduction simulation
+type SimulationParams struct {
+	Length        float64 `json:"length"`        // Length of plate (m)
+	TimeStep      float64 `json:"timeStep"`      // Time step (s)
+	SpaceStep     float64 `json:"spaceStep"`     // Space step (m)
+	TotalTime     float64 `json:"totalTime"`     // Total simulation time (s)
+	InitialTemp   float64 `json:"initialTemp"`   // Initial temperature (°C)
+	LeftBoundary  float64 `json:"leftBoundary"`  // Left boundary temperature (°C)
+	RightBoundary float64 `json:"rightBoundary"` // Right boundary temperature (°C)
+	Alpha         float64 `json:"alpha"`         // Thermal diffusivity (m²/s)
+
+	// Method selects the integration scheme: "explicit" (default, FTCS) or
+	// "crank-nicolson". Crank-Nicolson is unconditionally stable but costs
+	// a tridiagonal solve per step.
+	Method string `json:"method"`
+
+	// AutoStable, when the explicit scheme is requested but the Courant
+	// number exceeds the r <= 0.5 stability limit, switches to
+	// Crank-Nicolson instead of subdividing the time step.
+	//
+	// Method and AutoStable apply to the 1D rod only: the 2D plate always
+	// uses the explicit stencil and subdivides the time step.
+	AutoStable bool `json:"autoStable"`
+
+	// The fields below switch the simulation from the 1D rod (Length /
+	// SpaceStep) to a 2D rectangular plate: set Width, Height, Nx and Ny
+	// to simulate on an Nx*Ny grid instead. See heat2d.go.
+	Width      float64      `json:"width"`                // Plate width (m)
+	Height     float64      `json:"height"`               // Plate height (m)
+	Nx         int          `json:"nx"`                   // Grid points along x
+	Ny         int          `json:"ny"`                   // Grid points along y
+	Regions    []Region     `json:"regions,omitempty"`    // Per-region material overrides
+	Boundaries *Boundaries  `json:"boundaries,omitempty"` // Per-edge boundary conditions
+	Sources    []HeatSource `json:"sources,omitempty"`    // Internal heat sources Q(x,y,t)
+}
+
+// Is2D reports whether params describes a 2D plate rather than the legacy
+// 1D rod.
+func (p SimulationParams) Is2D() bool {
+	return p.Width > 0 && p.Height > 0 && p.Nx > 0 && p.Ny > 0
+}
+
+// SimulationResult contains a single reported frame of the simulation.
+type SimulationResult struct {
+	Temperatures []float64 `json:"temperatures"`
+	Time         float64   `json:"time"`
+	CenterTemp   float64   `json:"centerTemp"`
+	Stable       bool      `json:"stable"`
+	Method       string    `json:"method"`
+
+	// AdaptedTimeStep is set when the requested time step violated the
+	// explicit scheme's CFL condition and was automatically subdivided;
+	// zero otherwise.
+	AdaptedTimeStep float64 `json:"adaptedTimeStep,omitempty"`
+}
+
+// Default material: Aluminum
+// Thermal diffusivity α = k/(ρ*c) where:
+// k - thermal conductivity (W/(m·K))
+// ρ - density (kg/m³)
+// c - specific heat capacity (J/(kg·K))
+// For aluminum: k=237, ρ=2700, c=900
+// α ≈ 9.7e-5 m²/s
+
+// stepper advances a heat-conduction simulation (1D or 2D) one reported
+// frame at a time and can be reconfigured mid-run.
+type stepper interface {
+	result() SimulationResult
+	advance() (SimulationResult, bool)
+	reconfigure(params SimulationParams)
+
+	// shape returns [nx, ny] for a 2D plate, or nil for the 1D rod, whose
+	// Temperatures are implicitly a single row.
+	shape() []int
+
+	// resumeFrom rehydrates the field and elapsed time from a task
+	// checkpoint, so a resumed run continues rather than restarting.
+	// stepsDone is the step count the checkpoint was taken at.
+	resumeFrom(state tasks.CheckpointState, stepsDone int)
+}
+
+// newStepper builds the 1D or 2D stepper depending on whether params.Is2D.
+func newStepper(params SimulationParams) stepper {
+	if params.Is2D() {
+		return newHeat2D(params)
+	}
+	return newHeatStepper(params)
+}
+
+func withDefaults(params SimulationParams) SimulationParams {
+	if params.Alpha == 0 {
+		params.Alpha = 9.7e-5 // Aluminum
+	}
+	if params.Length == 0 {
+		params.Length = 1.0
+	}
+	if params.InitialTemp == 0 {
+		params.InitialTemp = 20.0
+	}
+	return params
+}
+
+// heatStepper holds the state needed to advance a heat-conduction
+// simulation one reported frame at a time, so the websocket handler can
+// stream frames as they are computed instead of buffering them all up
+// front.
+type heatStepper struct {
+	params SimulationParams
+
+	scheme          solver.Solver
+	dt              float64
+	adaptedTimeStep float64
+
+	T           []float64
+	centerIdx   int
+	currentTime float64
+
+	step        int
+	totalSteps  int
+	reportEvery int
+}
+
+func newHeatStepper(params SimulationParams) *heatStepper {
+	n := int(math.Ceil(params.Length/params.SpaceStep)) + 1
+
+	T := make([]float64, n)
+	for i := range T {
+		T[i] = params.InitialTemp
+	}
+	T[0] = params.LeftBoundary
+	T[n-1] = params.RightBoundary
+
+	s := &heatStepper{
+		params:    params,
+		T:         T,
+		centerIdx: n / 2,
+	}
+	s.reconfigure(params)
+	return s
+}
+
+// reconfigure rebuilds the integration scheme for new parameters without
+// resetting the temperature field or elapsed time, so a running simulation
+// can have its time step, diffusivity, method, or boundary values changed
+// mid-run (see updateParams in the websocket protocol). Grid size
+// (Length/SpaceStep) cannot be changed this way.
+func (s *heatStepper) reconfigure(params SimulationParams) {
+	method := params.Method
+	if method == "" {
+		method = "explicit"
+	}
+
+	dt := params.TimeStep
+	adaptedTimeStep := 0.0
+
+	// The explicit scheme is only conditionally stable; Crank-Nicolson has
+	// no such limit.
+	if method == "explicit" {
+		r := params.Alpha * dt / (params.SpaceStep * params.SpaceStep)
+		metrics.CFLRatio.Set(r)
+		if r > 0.5 {
+			metrics.UnstableRejectionsTotal.WithLabelValues("heat").Inc()
+			if params.AutoStable {
+				method = "crank-nicolson"
+			} else {
+				for params.Alpha*dt/(params.SpaceStep*params.SpaceStep) > 0.5 {
+					dt /= 2
+				}
+				adaptedTimeStep = dt
+			}
+		}
+	}
+
+	boundary := solver.Boundary{Left: params.LeftBoundary, Right: params.RightBoundary}
+	switch method {
+	case "crank-nicolson":
+		s.scheme = solver.NewCrankNicolson(params.Alpha, params.SpaceStep, boundary)
+	default:
+		s.scheme = solver.NewExplicitFTCS(params.Alpha, params.SpaceStep, boundary)
+	}
+
+	s.params = params
+	s.dt = dt
+	s.adaptedTimeStep = adaptedTimeStep
+	s.totalSteps = int(params.TotalTime / dt)
+	s.reportEvery = int(math.Max(1, math.Round(10*params.TimeStep/dt)))
+
+	// Boundary values may have changed; apply them immediately.
+	s.T[0] = params.LeftBoundary
+	s.T[len(s.T)-1] = params.RightBoundary
+}
+
+func (s *heatStepper) shape() []int { return nil }
+
+func (s *heatStepper) resumeFrom(state tasks.CheckpointState, stepsDone int) {
+	s.T = append([]float64{}, state.Field...)
+	s.currentTime = state.Time
+	s.step = stepsDone
+}
+
+func (s *heatStepper) result() SimulationResult {
+	return SimulationResult{
+		Temperatures:    append([]float64{}, s.T...),
+		Time:            s.currentTime,
+		CenterTemp:      s.T[s.centerIdx],
+		Stable:          true,
+		Method:          s.scheme.Name(),
+		AdaptedTimeStep: s.adaptedTimeStep,
+	}
+}
+
+// advance steps the simulation forward until the next frame is due to be
+// reported (or the run is finished) and returns the frame and whether the
+// run has more steps left to compute.
+func (s *heatStepper) advance() (SimulationResult, bool) {
+	for s.step < s.totalSteps {
+		s.T = s.scheme.Step(s.currentTime, s.T, s.dt)
+		s.currentTime += s.dt
+		metrics.StepsTotal.WithLabelValues("heat").Inc()
+		report := s.step%s.reportEvery == 0 || s.step == s.totalSteps-1
+		s.step++
+		if report {
+			return s.result(), s.step < s.totalSteps
+		}
+	}
+	return s.result(), false
+}
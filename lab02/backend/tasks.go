@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"galaxyginiti/simulation-course/pkg/tasks"
+)
+
+var errUnknownTask = errors.New("unknown task")
+
+// createTaskRequest is the body of POST /tasks: the same params the
+// websocket's "start" message takes, run as the same kind of persistent,
+// resumable task but with no websocket attached.
+type createTaskRequest struct {
+	Kind   string           `json:"kind"`
+	Params SimulationParams `json:"params"`
+}
+
+// handleTasksCollection serves POST /tasks (create).
+func handleTasksCollection(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	switch r.Method {
+	case http.MethodOptions:
+		w.WriteHeader(http.StatusOK)
+	case http.MethodPost:
+		createTask(w, r)
+	case http.MethodGet:
+		json.NewEncoder(w).Encode(taskStore.List())
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func createTask(w http.ResponseWriter, r *http.Request) {
+	var req createTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Kind == "" {
+		req.Kind = "heat"
+	}
+
+	task, _, err := startTask(req.Kind, req.Params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(task)
+}
+
+// handleTaskItem serves GET/DELETE /tasks/{id}, GET /tasks/{id}/artifact
+// and POST /tasks/{id}/resume.
+func handleTaskItem(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/tasks/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	id := parts[0]
+	if id == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		getTask(w, id)
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		deleteTask(w, id)
+	case len(parts) == 2 && parts[1] == "artifact" && r.Method == http.MethodGet:
+		downloadArtifact(w, r, id)
+	case len(parts) == 2 && parts[1] == "resume" && r.Method == http.MethodPost:
+		resumeTaskHandler(w, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func getTask(w http.ResponseWriter, id string) {
+	task, ok := taskStore.Get(id)
+	if !ok {
+		http.Error(w, "task not found", http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(task)
+}
+
+func deleteTask(w http.ResponseWriter, id string) {
+	if rt, ok := lookupRunning(id); ok {
+		rt.cancel()
+	}
+	if err := taskStore.Delete(id); err != nil {
+		http.Error(w, "task not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func downloadArtifact(w http.ResponseWriter, r *http.Request, id string) {
+	task, ok := taskStore.Get(id)
+	if !ok {
+		http.Error(w, "task not found", http.StatusNotFound)
+		return
+	}
+	if task.Status != tasks.StatusDone {
+		http.Error(w, "artifact not ready", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+id+`.h5.gz"`)
+	http.ServeFile(w, r, taskStore.ArtifactPath(id))
+}
+
+func resumeTaskHandler(w http.ResponseWriter, id string) {
+	if _, running := lookupRunning(id); running {
+		http.Error(w, "task is already running", http.StatusConflict)
+		return
+	}
+	if !taskStore.HasCheckpoint(id) {
+		http.Error(w, "no checkpoint to resume from", http.StatusConflict)
+		return
+	}
+
+	if _, err := resumeTask(id); err != nil {
+		if errors.Is(err, errUnknownTask) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, errAlreadyRunning) {
+			http.Error(w, "task is already running", http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	task, _ := taskStore.Get(id)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(task)
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"galaxyginiti/simulation-course/pkg/metrics"
+)
+
+const (
+	writeWait         = 10 * time.Second
+	pongWait          = 60 * time.Second
+	pingPeriod        = (pongWait * 9) / 10
+	maxMessageSize    = 1 << 20 // 1 MiB
+	defaultBufferSize = 16      // frame channel capacity
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// handshake is the first message a client sends on the websocket; it
+// configures how simulation frames are delivered for the rest of the
+// connection's lifetime.
+type handshake struct {
+	Format string `json:"format"` // "json" (default) or "binary"
+	Gzip   bool   `json:"gzip"`   // gzip-compress binary frames
+
+	// Backpressure selects what this connection's frame bus does when the
+	// client falls behind: "block" (default) holds each frame until the
+	// writer goroutine has room, "dropOldest" discards the oldest buffered
+	// frame to make room for the newest. Neither pauses the simulation:
+	// runningTask.broadcast never waits for a viewer, so frames that arrive
+	// while this viewer's subscription buffer is full are dropped.
+	Backpressure string `json:"backpressure"`
+	BufferSize   int    `json:"bufferSize"` // frame channel capacity, default 16
+}
+
+// clientMessage is the envelope for every message a client sends after the
+// handshake: either a request to start a run, or a control message
+// targeting the currently running simulation.
+type clientMessage struct {
+	Type      string            `json:"type"` // "handshake" | "start" | "pause" | "resume" | "stop" | "updateParams"
+	Handshake *handshake        `json:"handshake,omitempty"`
+	Params    *SimulationParams `json:"params,omitempty"`
+}
+
+// frameBus is the bounded channel between a task's live frames and the
+// writer goroutine. It bounds how many frames are queued for a slow
+// websocket consumer, so the server never buffers every frame in RAM; it
+// does not slow the simulation itself down (see handshake.Backpressure).
+type frameBus struct {
+	frames     chan SimulationResult
+	dropOldest bool
+}
+
+func newFrameBus(size int, dropOldest bool) *frameBus {
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+	return &frameBus{frames: make(chan SimulationResult, size), dropOldest: dropOldest}
+}
+
+// send delivers result to the bus. With dropOldest it never blocks the
+// caller: it discards the oldest queued frame to make room. Otherwise it
+// blocks (respecting ctx cancellation) until the writer goroutine has
+// room; while it waits, relayToBus stops draining the task subscription,
+// and runningTask.broadcast drops the frames that do not fit there.
+func (b *frameBus) send(ctx context.Context, result SimulationResult) {
+	if !b.dropOldest {
+		select {
+		case b.frames <- result:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	for {
+		select {
+		case b.frames <- result:
+			return
+		default:
+		}
+		select {
+		case <-b.frames:
+		default:
+		}
+	}
+}
+
+// relayToBus forwards a running task's live frames onto bus until stop is
+// closed (the viewer detached) or sub is closed by unsubscribe. A task
+// that finishes does not close sub, so after its last frame the relay
+// waits until the viewer detaches. It is the bridge between a runningTask's broadcast fan-out and this
+// connection's own backpressure policy.
+func relayToBus(stop <-chan struct{}, sub <-chan SimulationResult, bus *frameBus) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		select {
+		case result, ok := <-sub:
+			if !ok {
+				close(bus.frames)
+				return
+			}
+			bus.send(ctx, result)
+		case <-ctx.Done():
+			close(bus.frames)
+			return
+		}
+	}
+}
+
+// streamFrames drains bus, encoding each frame as JSON or as the compact
+// binary envelope (little-endian uint32 frame-id, float64 time, uint32 N,
+// then N float64 temperatures), optionally gzip-wrapped, per the
+// handshake. It returns once bus.frames is closed or a write fails.
+func streamFrames(bus *frameBus, hs handshake, writeJSON func(interface{}) error, writeBinary func([]byte) error) {
+	var frameID uint32
+	for result := range bus.frames {
+		frameID++
+
+		if hs.Format != "binary" {
+			if err := writeJSON(result); err != nil {
+				log.Println("Write error:", err)
+				return
+			}
+			continue
+		}
+
+		payload, err := encodeBinaryFrame(frameID, result)
+		if err != nil {
+			log.Println("Encode error:", err)
+			return
+		}
+		if hs.Gzip {
+			if payload, err = gzipBytes(payload); err != nil {
+				log.Println("Gzip error:", err)
+				return
+			}
+		}
+		if err := writeBinary(payload); err != nil {
+			log.Println("Write error:", err)
+			return
+		}
+	}
+}
+
+func encodeBinaryFrame(frameID uint32, result SimulationResult) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	for _, v := range []interface{}{frameID, result.Time, uint32(len(result.Temperatures)), result.Temperatures} {
+		if err := binary.Write(buf, binary.LittleEndian, v); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func gzipBytes(b []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	gw := gzip.NewWriter(buf)
+	if _, err := gw.Write(b); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func handleSimulation(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println("Upgrade error:", err)
+		return
+	}
+	defer conn.Close()
+
+	metrics.ActiveWebsockets.Inc()
+	defer metrics.ActiveWebsockets.Dec()
+
+	conn.SetReadLimit(maxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	// All writes (frames, errors, pings) share one connection, so they are
+	// serialized through this mutex.
+	var writeMu sync.Mutex
+	writeJSON := func(v interface{}) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.SetWriteDeadline(time.Now().Add(writeWait))
+		return conn.WriteJSON(v)
+	}
+	writeBinary := func(b []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.SetWriteDeadline(time.Now().Add(writeWait))
+		return conn.WriteMessage(websocket.BinaryMessage, b)
+	}
+
+	pingDone := make(chan struct{})
+	defer close(pingDone)
+	go func() {
+		ticker := time.NewTicker(pingPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				writeMu.Lock()
+				conn.SetWriteDeadline(time.Now().Add(writeWait))
+				err := conn.WriteMessage(websocket.PingMessage, nil)
+				writeMu.Unlock()
+				if err != nil {
+					return
+				}
+			case <-pingDone:
+				return
+			}
+		}
+	}()
+
+	hs := handshake{Format: "json", Backpressure: "block", BufferSize: defaultBufferSize}
+
+	// The connection is a thin live view over a task running in the
+	// background (see runtime.go): rt keeps going, checkpointing and
+	// streaming to its artifact, even after this socket detaches from it.
+	var (
+		rt       *runningTask
+		sub      chan SimulationResult
+		viewStop chan struct{}
+	)
+	detach := func() {
+		if rt == nil {
+			return
+		}
+		close(viewStop)
+		rt.unsubscribe(sub)
+		rt, sub, viewStop = nil, nil, nil
+	}
+	stop := func() {
+		if rt == nil {
+			return
+		}
+		rt.cancel()
+		detach()
+	}
+	defer detach()
+
+	for {
+		var msg clientMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			log.Println("Read error:", err)
+			return
+		}
+
+		switch msg.Type {
+		case "handshake":
+			if msg.Handshake != nil {
+				hs = *msg.Handshake
+				if hs.Format == "" {
+					hs.Format = "json"
+				}
+				if hs.Backpressure == "" {
+					hs.Backpressure = "block"
+				}
+				if hs.BufferSize <= 0 {
+					hs.BufferSize = defaultBufferSize
+				}
+			}
+
+		case "start":
+			if msg.Params == nil {
+				writeJSON(map[string]interface{}{"error": "start requires params"})
+				continue
+			}
+			detach()
+
+			params := withDefaults(*msg.Params)
+			if params.Is2D() {
+				writeJSON(map[string]interface{}{"type": "shape", "shape": []int{params.Nx, params.Ny}})
+			}
+
+			task, newRt, err := startTask("heat", *msg.Params)
+			if err != nil {
+				writeJSON(map[string]interface{}{"error": err.Error()})
+				continue
+			}
+			writeJSON(map[string]interface{}{"type": "task", "id": task.ID})
+
+			rt = newRt
+			sub = rt.subscribe()
+			viewStop = make(chan struct{})
+
+			bus := newFrameBus(hs.BufferSize, hs.Backpressure == "dropOldest")
+			go relayToBus(viewStop, sub, bus)
+			go streamFrames(bus, hs, writeJSON, writeBinary)
+
+		case "pause", "resume", "updateParams":
+			if rt != nil {
+				select {
+				case rt.control <- msg:
+				default:
+				}
+			}
+
+		case "stop":
+			stop()
+
+		default:
+			writeJSON(map[string]interface{}{"error": fmt.Sprintf("unknown message type %q", msg.Type)})
+		}
+	}
+}
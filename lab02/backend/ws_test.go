@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// decodeBinaryFrame reverses encodeBinaryFrame's layout: little-endian
+// uint32 frame-id, float64 time, uint32 N, then N float64 temperatures.
+func decodeBinaryFrame(t *testing.T, payload []byte) (frameID uint32, simTime float64, temps []float64) {
+	t.Helper()
+	r := bytes.NewReader(payload)
+
+	var n uint32
+	for _, v := range []interface{}{&frameID, &simTime, &n} {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			t.Fatalf("decode header: %v", err)
+		}
+	}
+	temps = make([]float64, n)
+	if err := binary.Read(r, binary.LittleEndian, &temps); err != nil {
+		t.Fatalf("decode temperatures: %v", err)
+	}
+	if r.Len() != 0 {
+		t.Fatalf("%d trailing bytes after decoding frame", r.Len())
+	}
+	return frameID, simTime, temps
+}
+
+func TestEncodeBinaryFrameRoundTrip(t *testing.T) {
+	result := SimulationResult{Time: 1.5, Temperatures: []float64{20, 21.5, 22, 19.25}}
+
+	payload, err := encodeBinaryFrame(7, result)
+	if err != nil {
+		t.Fatalf("encodeBinaryFrame: %v", err)
+	}
+
+	gotID, gotTime, gotTemps := decodeBinaryFrame(t, payload)
+	if gotID != 7 {
+		t.Errorf("frameID = %d, want 7", gotID)
+	}
+	if gotTime != result.Time {
+		t.Errorf("time = %v, want %v", gotTime, result.Time)
+	}
+	if len(gotTemps) != len(result.Temperatures) {
+		t.Fatalf("got %d temperatures, want %d", len(gotTemps), len(result.Temperatures))
+	}
+	for i, v := range gotTemps {
+		if v != result.Temperatures[i] {
+			t.Errorf("temperatures[%d] = %v, want %v", i, v, result.Temperatures[i])
+		}
+	}
+}
+
+// TestFrameBusDropOldestDiscardsOldest checks that a dropOldest bus never
+// blocks the sender: once full, it discards the oldest buffered frame to
+// make room for the newest rather than waiting for the reader.
+func TestFrameBusDropOldestDiscardsOldest(t *testing.T) {
+	bus := newFrameBus(2, true)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		done := make(chan struct{})
+		go func(i int) {
+			bus.send(ctx, SimulationResult{Time: float64(i)})
+			close(done)
+		}(i)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("send(%d) blocked with dropOldest set", i)
+		}
+	}
+
+	var got []float64
+	for i := 0; i < 2; i++ {
+		got = append(got, (<-bus.frames).Time)
+	}
+	if got[0] != 1 || got[1] != 2 {
+		t.Errorf("buffered times = %v, want [1 2] (frame 0 should have been dropped)", got)
+	}
+}
+
+// TestFrameBusBlockWaitsForRoom checks that a non-dropOldest bus blocks the
+// sender until the reader makes room, instead of discarding anything.
+func TestFrameBusBlockWaitsForRoom(t *testing.T) {
+	bus := newFrameBus(1, false)
+	ctx := context.Background()
+
+	bus.send(ctx, SimulationResult{Time: 0})
+
+	sent := make(chan struct{})
+	go func() {
+		bus.send(ctx, SimulationResult{Time: 1})
+		close(sent)
+	}()
+
+	select {
+	case <-sent:
+		t.Fatal("send completed before the reader made room")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if got := <-bus.frames; got.Time != 0 {
+		t.Fatalf("first frame = %v, want 0", got.Time)
+	}
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("send did not complete after the reader made room")
+	}
+	if got := <-bus.frames; got.Time != 1 {
+		t.Fatalf("second frame = %v, want 1", got.Time)
+	}
+}
@@ -0,0 +1,35 @@
+package ballistics
+
+import "math"
+
+// ISA (International Standard Atmosphere) troposphere constants.
+const (
+	isaRho0 = 1.225     // sea-level density, kg/m³
+	isaL    = 0.0065    // temperature lapse rate, K/m
+	isaT0   = 288.15    // sea-level temperature, K
+	isaM    = 0.0289644 // molar mass of air, kg/mol
+	isaR    = 8.3144598 // universal gas constant, J/(mol·K)
+	isaG    = 9.80665   // standard gravity used by the ISA model, m/s²
+)
+
+// ISADensity returns the air density (kg/m³) at altitude h (m) above sea
+// level, per the ISA troposphere model:
+//
+//	ρ(h) = ρ₀·(1 − L·h/T₀)^(g·M/(R·L) − 1)
+//
+// Valid for h in [0, 11000] m. Negative altitudes clamp to 0; altitudes
+// above T₀/L ≈ 44331 m (where the bracketed term would go negative) clamp
+// to that height, where the density is 0. Between 11000 m and that height
+// the troposphere formula is still applied.
+func ISADensity(h float64) float64 {
+	if h < 0 {
+		h = 0
+	}
+	const troposphereTop = isaT0 / isaL // ~44330.77 m, where the bracket hits 0
+	if h > troposphereTop {
+		h = troposphereTop
+	}
+
+	exponent := isaG*isaM/(isaR*isaL) - 1
+	return isaRho0 * math.Pow(1-isaL*h/isaT0, exponent)
+}
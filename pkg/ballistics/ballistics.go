@@ -0,0 +1,133 @@
+// Package ballistics models 3-DOF projectile motion: a point mass with
+// position, velocity and spin, subject to quadratic drag, wind, the
+// Magnus effect and (optionally) Coriolis acceleration, integrated with
+// an adaptive embedded Runge-Kutta scheme. Axes are x (downrange), y
+// (crossrange) and z (altitude), matching the ISA atmosphere model in
+// atmosphere.go, which is a function of z alone.
+package ballistics
+
+import "math"
+
+// State is a projectile's full 9-component state: position (X, Y, Z),
+// velocity (Vx, Vy, Vz) and spin (Wx, Wy, Wz) in rad/s about each axis.
+type State struct {
+	X, Y, Z    float64
+	Vx, Vy, Vz float64
+	Wx, Wy, Wz float64
+}
+
+// Vector flattens s into the 9-element layout pkg/solver's Derivative
+// functions and RKF45 operate on: [x,y,z, vx,vy,vz, wx,wy,wz].
+func (s State) Vector() []float64 {
+	return []float64{s.X, s.Y, s.Z, s.Vx, s.Vy, s.Vz, s.Wx, s.Wy, s.Wz}
+}
+
+// StateFromVector reverses Vector.
+func StateFromVector(v []float64) State {
+	return State{
+		X: v[0], Y: v[1], Z: v[2],
+		Vx: v[3], Vy: v[4], Vz: v[5],
+		Wx: v[6], Wy: v[7], Wz: v[8],
+	}
+}
+
+// Speed returns the state's velocity magnitude.
+func (s State) Speed() float64 {
+	return math.Sqrt(s.Vx*s.Vx + s.Vy*s.Vy + s.Vz*s.Vz)
+}
+
+// PlanarState builds the initial state for a classic 2D shot (no
+// crossrange, no spin): v0 at angle (degrees) above the horizontal,
+// launched from altitude h0. It exists so the legacy 2D endpoint can run
+// through this package's engine by projecting into the zero-wind,
+// zero-spin plane (Y = Vy = Wx = Wy = Wz = 0) instead of duplicating the
+// force model.
+func PlanarState(v0, angleDeg, h0 float64) State {
+	angle := angleDeg * math.Pi / 180
+	return State{
+		Z:  h0,
+		Vx: v0 * math.Cos(angle),
+		Vz: v0 * math.Sin(angle),
+	}
+}
+
+// Params are the physical properties of the projectile and environment
+// that don't change over the course of one simulation.
+type Params struct {
+	Mass float64 // kg
+	Area float64 // cross-sectional area, m²
+	Cd   float64 // drag coefficient
+	Cl   float64 // Magnus lift coefficient; 0 disables the Magnus term
+
+	Wind WindField // nil is treated as ZeroWind
+
+	// Coriolis is the planet's angular velocity vector (rad/s); nil
+	// disables the Coriolis term. For Earth at latitude φ this is
+	// Ω*(0, cos φ, sin φ) with Ω ≈ 7.2921e-5 rad/s, in the (x,y,z)
+	// convention above (y north, z up).
+	Coriolis *[3]float64
+
+	Gravity float64 // m/s², defaults to 9.80665 (standard gravity) if 0
+}
+
+func (p Params) gravity() float64 {
+	if p.Gravity == 0 {
+		return 9.80665
+	}
+	return p.Gravity
+}
+
+func (p Params) wind() WindField {
+	if p.Wind == nil {
+		return ZeroWind{}
+	}
+	return p.Wind
+}
+
+// NewDerivative returns the 9-component state derivative for p, in the
+// form pkg/solver's Solver implementations and RKF45 expect: d/dt
+// [x,y,z,vx,vy,vz,wx,wy,wz].
+func NewDerivative(p Params) func(t float64, state []float64) []float64 {
+	gravity := p.gravity()
+	wind := p.wind()
+
+	return func(_ float64, state []float64) []float64 {
+		s := StateFromVector(state)
+
+		wx, wy, wz := wind.At(s.X, s.Y, s.Z)
+		vRel := [3]float64{s.Vx - wx, s.Vy - wy, s.Vz - wz}
+		speed := math.Sqrt(vRel[0]*vRel[0] + vRel[1]*vRel[1] + vRel[2]*vRel[2])
+
+		rho := ISADensity(s.Z)
+
+		accel := [3]float64{0, 0, -gravity}
+
+		if speed > 0 {
+			dragScale := 0.5 * rho * p.Cd * p.Area * speed / p.Mass
+			for i := 0; i < 3; i++ {
+				accel[i] -= dragScale * vRel[i]
+			}
+
+			if p.Cl != 0 {
+				omega := [3]float64{s.Wx, s.Wy, s.Wz}
+				magnus := MagnusForce(rho, p.Cl, p.Area, omega, vRel)
+				for i := 0; i < 3; i++ {
+					accel[i] += magnus[i] / p.Mass
+				}
+			}
+		}
+
+		if p.Coriolis != nil {
+			cor := CoriolisAcceleration(*p.Coriolis, [3]float64{s.Vx, s.Vy, s.Vz})
+			for i := 0; i < 3; i++ {
+				accel[i] += cor[i]
+			}
+		}
+
+		return []float64{
+			s.Vx, s.Vy, s.Vz,
+			accel[0], accel[1], accel[2],
+			0, 0, 0, // no torque model: spin is carried, not damped
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package ballistics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestISADensityAtSeaLevel(t *testing.T) {
+	got := ISADensity(0)
+	if math.Abs(got-1.225) > 1e-3 {
+		t.Errorf("ISADensity(0) = %v, want ~1.225", got)
+	}
+}
+
+func TestISADensityDecreasesWithAltitude(t *testing.T) {
+	low := ISADensity(0)
+	high := ISADensity(5000)
+	if high >= low {
+		t.Errorf("ISADensity(5000) = %v, want less than ISADensity(0) = %v", high, low)
+	}
+}
+
+func TestConstantWindIsUniform(t *testing.T) {
+	w := ConstantWind{Vx: 3, Vy: -1, Vz: 0.5}
+	vx, vy, vz := w.At(100, 200, 300)
+	if vx != 3 || vy != -1 || vz != 0.5 {
+		t.Errorf("At() = (%v,%v,%v), want (3,-1,0.5)", vx, vy, vz)
+	}
+}
+
+func TestLayeredWindInterpolatesBetweenLayers(t *testing.T) {
+	w := LayeredWind{Layers: []WindLayer{
+		{Height: 0, Vx: 0},
+		{Height: 100, Vx: 10},
+	}}
+	vx, _, _ := w.At(0, 0, 50)
+	if math.Abs(vx-5) > 1e-9 {
+		t.Errorf("At(z=50) Vx = %v, want 5", vx)
+	}
+
+	vxLow, _, _ := w.At(0, 0, -10)
+	if vxLow != 0 {
+		t.Errorf("At(z=-10) Vx = %v, want clamped to 0", vxLow)
+	}
+	vxHigh, _, _ := w.At(0, 0, 1000)
+	if vxHigh != 10 {
+		t.Errorf("At(z=1000) Vx = %v, want clamped to 10", vxHigh)
+	}
+}
+
+func TestGridWindBilinearInterpolation(t *testing.T) {
+	g := GridWind{
+		Xs: []float64{0, 10},
+		Ys: []float64{0, 10},
+		Vx: [][]float64{{0, 0}, {10, 10}},
+		Vy: [][]float64{{0, 0}, {0, 0}},
+		Vz: [][]float64{{0, 0}, {0, 0}},
+	}
+	vx, _, _ := g.At(5, 5, 0)
+	if math.Abs(vx-5) > 1e-9 {
+		t.Errorf("At(5,5) Vx = %v, want 5", vx)
+	}
+}
+
+func TestMagnusForcePerpendicularToSpinAndVelocity(t *testing.T) {
+	omega := [3]float64{0, 0, 1}
+	vRel := [3]float64{10, 0, 0}
+	f := MagnusForce(1.2, 0.2, 0.01, omega, vRel)
+	if math.Abs(f[2]) > 1e-12 {
+		t.Errorf("MagnusForce z-component = %v, want 0 (spin about z, velocity in x)", f[2])
+	}
+	if f[1] == 0 {
+		t.Error("MagnusForce y-component = 0, want nonzero deflection")
+	}
+}
+
+// TestRKF45AgainstHarmonicOscillator checks the adaptive integrator
+// against a known analytic ODE, y'' = -y, independent of the projectile
+// force model.
+func TestRKF45AgainstHarmonicOscillator(t *testing.T) {
+	deriv := func(_ float64, y []float64) []float64 {
+		return []float64{y[1], -y[0]} // y'' = -y
+	}
+	r := NewRKF45(deriv)
+	r.Tol = 1e-9
+
+	y := []float64{1, 0} // x(0)=1, x'(0)=0 -> x(t) = cos(t)
+	tt := 0.0
+	dt := 0.1
+	for tt < 2*math.Pi {
+		next, dtUsed, dtNext := r.Step(tt, y, dt)
+		y = next
+		tt += dtUsed
+		dt = dtNext
+	}
+
+	want := math.Cos(tt)
+	if math.Abs(y[0]-want) > 1e-5 {
+		t.Errorf("x(%v) = %v, want %v", tt, y[0], want)
+	}
+}
+
+func TestNewDerivativeGravityOnlyMatchesFreeFall(t *testing.T) {
+	params := Params{Mass: 1, Area: 0, Cd: 0, Cl: 0}
+	deriv := NewDerivative(params)
+
+	state := PlanarState(0, 0, 100).Vector()
+	d := deriv(0, state)
+	if math.Abs(d[5]-(-9.80665)) > 1e-6 {
+		t.Errorf("vertical acceleration = %v, want -9.80665 (no drag, area=0)", d[5])
+	}
+}
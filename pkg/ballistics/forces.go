@@ -0,0 +1,26 @@
+package ballistics
+
+// cross returns a × b.
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+// MagnusForce returns the Magnus lift force F_M = ½·ρ·Cl·A·(ω × v_rel)
+// on a spinning projectile, in Newtons.
+func MagnusForce(rho, cl, area float64, omega, vRel [3]float64) [3]float64 {
+	c := cross(omega, vRel)
+	scale := 0.5 * rho * cl * area
+	return [3]float64{scale * c[0], scale * c[1], scale * c[2]}
+}
+
+// CoriolisAcceleration returns the Coriolis acceleration −2·Ω × v for a
+// projectile moving with velocity v on a planet rotating at angular
+// velocity omega, in m/s². It's only significant for long-range shots.
+func CoriolisAcceleration(omega, v [3]float64) [3]float64 {
+	c := cross(omega, v)
+	return [3]float64{-2 * c[0], -2 * c[1], -2 * c[2]}
+}
@@ -0,0 +1,157 @@
+package ballistics
+
+import (
+	"math"
+
+	"galaxyginiti/simulation-course/pkg/solver"
+)
+
+// RKF45 is an adaptive Runge-Kutta-Fehlberg 4(5) integrator: it advances
+// the state with the embedded 4th- and 5th-order solutions, uses their
+// difference as a local error estimate, and shrinks or grows the step to
+// keep that error under Tol. This is what replaces the fixed-step Euler
+// integration the 2D endpoint used to do, letting the fast-moving parts
+// of a trajectory take small steps without forcing small steps
+// everywhere else.
+type RKF45 struct {
+	Deriv solver.Derivative
+
+	Tol          float64 // desired per-component error tolerance (RMS); defaults to 1e-6
+	MinStep      float64 // smallest step it will take before giving up and accepting the error; defaults to 1e-6
+	MaxStep      float64 // largest step it will propose; defaults to 1.0
+	SafetyFactor float64 // shrinks the proposed step below the theoretical optimum; defaults to 0.9
+}
+
+// NewRKF45 builds an RKF45 integrator with the package's default
+// tolerance and step bounds.
+func NewRKF45(deriv solver.Derivative) *RKF45 {
+	return &RKF45{Deriv: deriv, Tol: 1e-6, MinStep: 1e-6, MaxStep: 1.0, SafetyFactor: 0.9}
+}
+
+func (r *RKF45) tol() float64 {
+	if r.Tol == 0 {
+		return 1e-6
+	}
+	return r.Tol
+}
+
+func (r *RKF45) minStep() float64 {
+	if r.MinStep == 0 {
+		return 1e-6
+	}
+	return r.MinStep
+}
+
+func (r *RKF45) maxStep() float64 {
+	if r.MaxStep == 0 {
+		return 1.0
+	}
+	return r.MaxStep
+}
+
+func (r *RKF45) safety() float64 {
+	if r.SafetyFactor == 0 {
+		return 0.9
+	}
+	return r.SafetyFactor
+}
+
+// Step advances state from t by up to dtGuess, internally halving the
+// step until the embedded error estimate is within tolerance (or dtGuess
+// has shrunk to MinStep, at which point it accepts the result anyway so
+// the integration can't stall forever). It returns the next state, the
+// step actually taken, and a suggested step size for the caller's next
+// call.
+func (r *RKF45) Step(t float64, y []float64, dtGuess float64) (next []float64, dtUsed, dtNext float64) {
+	dt := dtGuess
+	minStep := r.minStep()
+
+	for {
+		y4, y5 := r.trial(t, y, dt)
+		errNorm := errorNorm(y4, y5)
+
+		if errNorm <= r.tol() || dt <= minStep {
+			suggested := r.grow(dt, errNorm)
+			return y5, dt, suggested
+		}
+		dt = math.Max(dt/2, minStep)
+	}
+}
+
+// trial evaluates one RKF45 step, returning the 4th- and 5th-order
+// solutions from the same six derivative evaluations (the embedded
+// pair).
+func (r *RKF45) trial(t float64, y []float64, dt float64) (y4, y5 []float64) {
+	n := len(y)
+	k1 := r.Deriv(t, y)
+
+	y2 := addScaled(y, n, dt, []term{{1.0 / 4, k1}})
+	k2 := r.Deriv(t+dt/4, y2)
+
+	y3 := addScaled(y, n, dt, []term{{3.0 / 32, k1}, {9.0 / 32, k2}})
+	k3 := r.Deriv(t+3*dt/8, y3)
+
+	y4in := addScaled(y, n, dt, []term{{1932.0 / 2197, k1}, {-7200.0 / 2197, k2}, {7296.0 / 2197, k3}})
+	k4 := r.Deriv(t+12*dt/13, y4in)
+
+	y5in := addScaled(y, n, dt, []term{{439.0 / 216, k1}, {-8, k2}, {3680.0 / 513, k3}, {-845.0 / 4104, k4}})
+	k5 := r.Deriv(t+dt, y5in)
+
+	y6in := addScaled(y, n, dt, []term{{-8.0 / 27, k1}, {2, k2}, {-3544.0 / 2565, k3}, {1859.0 / 4104, k4}, {-11.0 / 40, k5}})
+	k6 := r.Deriv(t+dt/2, y6in)
+
+	y4 = addScaled(y, n, dt, []term{
+		{25.0 / 216, k1}, {1408.0 / 2565, k3}, {2197.0 / 4104, k4}, {-1.0 / 5, k5},
+	})
+	y5 = addScaled(y, n, dt, []term{
+		{16.0 / 135, k1}, {6656.0 / 12825, k3}, {28561.0 / 56430, k4}, {-9.0 / 50, k5}, {2.0 / 55, k6},
+	})
+	return y4, y5
+}
+
+// term is one coefficient*derivative pair accumulated by addScaled.
+type term struct {
+	coeff float64
+	k     []float64
+}
+
+// addScaled returns base + dt*sum(t.coeff*t.k) componentwise.
+func addScaled(base []float64, n int, dt float64, terms []term) []float64 {
+	out := make([]float64, n)
+	copy(out, base)
+	for _, term := range terms {
+		for i := 0; i < n; i++ {
+			out[i] += dt * term.coeff * term.k[i]
+		}
+	}
+	return out
+}
+
+// errorNorm is the RMS difference between the two embedded solutions.
+func errorNorm(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum / float64(len(a)))
+}
+
+// grow proposes the next step size from the classical RKF step-control
+// formula, clamped to [MinStep, MaxStep] and to at most 4x growth so a
+// lucky low-error step doesn't overshoot wildly.
+func (r *RKF45) grow(dt, errNorm float64) float64 {
+	if errNorm == 0 {
+		return math.Min(dt*4, r.maxStep())
+	}
+	factor := r.safety() * math.Pow(r.tol()/errNorm, 0.2)
+	factor = math.Max(0.1, math.Min(4, factor))
+	next := dt * factor
+	if next < r.minStep() {
+		next = r.minStep()
+	}
+	if next > r.maxStep() {
+		next = r.maxStep()
+	}
+	return next
+}
@@ -0,0 +1,111 @@
+package ballistics
+
+import "sort"
+
+// WindField returns the wind velocity vector (m/s) at a position.
+type WindField interface {
+	At(x, y, z float64) (vx, vy, vz float64)
+}
+
+// ZeroWind is the still-air field; it's what Params.Wind defaults to.
+type ZeroWind struct{}
+
+func (ZeroWind) At(x, y, z float64) (float64, float64, float64) { return 0, 0, 0 }
+
+// ConstantWind is the same velocity everywhere.
+type ConstantWind struct {
+	Vx, Vy, Vz float64
+}
+
+func (w ConstantWind) At(x, y, z float64) (float64, float64, float64) { return w.Vx, w.Vy, w.Vz }
+
+// WindLayer is one altitude band of a LayeredWind.
+type WindLayer struct {
+	Height     float64 // m above sea level
+	Vx, Vy, Vz float64
+}
+
+// LayeredWind varies with altitude only, linearly interpolating between
+// the two layers bracketing z (clamping to the lowest/highest layer
+// outside that range). Layers need not be given in sorted order.
+type LayeredWind struct {
+	Layers []WindLayer
+}
+
+// sorted returns w.Layers ordered by Height, without mutating w.
+func (w LayeredWind) sorted() []WindLayer {
+	layers := append([]WindLayer(nil), w.Layers...)
+	sort.Slice(layers, func(i, j int) bool { return layers[i].Height < layers[j].Height })
+	return layers
+}
+
+func (w LayeredWind) At(x, y, z float64) (float64, float64, float64) {
+	layers := w.sorted()
+	if len(layers) == 0 {
+		return 0, 0, 0
+	}
+	if z <= layers[0].Height {
+		return layers[0].Vx, layers[0].Vy, layers[0].Vz
+	}
+	last := layers[len(layers)-1]
+	if z >= last.Height {
+		return last.Vx, last.Vy, last.Vz
+	}
+
+	for i := 1; i < len(layers); i++ {
+		upper := layers[i]
+		if z > upper.Height {
+			continue
+		}
+		lower := layers[i-1]
+		frac := (z - lower.Height) / (upper.Height - lower.Height)
+		return lerp(lower.Vx, upper.Vx, frac), lerp(lower.Vy, upper.Vy, frac), lerp(lower.Vz, upper.Vz, frac)
+	}
+	return last.Vx, last.Vy, last.Vz
+}
+
+func lerp(a, b, frac float64) float64 { return a + (b-a)*frac }
+
+// GridWind bilinearly interpolates a horizontal wind field sampled on a
+// regular (Xs × Ys) grid; it doesn't vary with altitude. Vx, Vy and Vz
+// are indexed [j][i], i.e. Vx[j][i] is the x-wind at (Xs[i], Ys[j]). Xs
+// and Ys must each be sorted ascending.
+type GridWind struct {
+	Xs, Ys     []float64
+	Vx, Vy, Vz [][]float64
+}
+
+func (g GridWind) At(x, y, z float64) (float64, float64, float64) {
+	if len(g.Xs) == 0 || len(g.Ys) == 0 {
+		return 0, 0, 0
+	}
+	i0, i1, fx := bracket(g.Xs, x)
+	j0, j1, fy := bracket(g.Ys, y)
+
+	bilerp := func(grid [][]float64) float64 {
+		top := lerp(grid[j0][i0], grid[j0][i1], fx)
+		bottom := lerp(grid[j1][i0], grid[j1][i1], fx)
+		return lerp(top, bottom, fy)
+	}
+	return bilerp(g.Vx), bilerp(g.Vy), bilerp(g.Vz)
+}
+
+// bracket finds the pair of indices in sorted xs bracketing v, clamping
+// to the ends, and the interpolation fraction between them.
+func bracket(xs []float64, v float64) (lo, hi int, frac float64) {
+	if len(xs) == 1 {
+		return 0, 0, 0
+	}
+	if v <= xs[0] {
+		return 0, 1, 0
+	}
+	if v >= xs[len(xs)-1] {
+		return len(xs) - 2, len(xs) - 1, 1
+	}
+	for i := 1; i < len(xs); i++ {
+		if v <= xs[i] {
+			return i - 1, i, (v - xs[i-1]) / (xs[i] - xs[i-1])
+		}
+	}
+	return len(xs) - 2, len(xs) - 1, 1
+}
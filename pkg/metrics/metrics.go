@@ -0,0 +1,77 @@
+// Package metrics holds the Prometheus collectors shared by the simulation
+// backends and a helper to serve them on their own port, so the internal
+// health/performance surface is never exposed alongside the public API.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// RequestsTotal counts simulation requests by kind (e.g. "heat",
+	// "projectile", "projectile3d") and outcome ("success", "invalid",
+	// "resumed").
+	RequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sim_requests_total",
+		Help: "Total number of simulation requests, by kind and status.",
+	}, []string{"kind", "status"})
+
+	// DurationSeconds measures how long a simulation run took to compute,
+	// from first step to last, by kind.
+	DurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "sim_duration_seconds",
+		Help:    "Time spent computing a simulation run, by kind.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"kind"})
+
+	// StepsTotal counts the number of integration steps computed, by kind.
+	StepsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sim_steps_total",
+		Help: "Total number of integration steps computed, by kind.",
+	}, []string{"kind"})
+
+	// ActiveWebsockets is the number of currently open simulation
+	// websocket connections.
+	ActiveWebsockets = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "sim_active_websockets",
+		Help: "Number of currently open simulation websocket connections.",
+	})
+
+	// UnstableRejectionsTotal counts how often a heat-conduction request's
+	// Courant number exceeded the explicit scheme's r <= 0.5 stability
+	// limit and had to be auto-subdivided or switched to Crank-Nicolson.
+	UnstableRejectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sim_unstable_rejections_total",
+		Help: "Number of requests whose explicit-scheme CFL number exceeded 0.5, by kind.",
+	}, []string{"kind"})
+
+	// CFLRatio is the last observed Courant number for the explicit
+	// heat-conduction scheme: r = alpha*dt/dx^2 for the 1D rod, or
+	// alpha_max*dt*(1/dx^2 + 1/dy^2) for the 2D plate.
+	CFLRatio = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "sim_cfl_ratio",
+		Help: "Last observed Courant number (alpha*dt/dx^2 in 1D, alpha*dt*(1/dx^2+1/dy^2) in 2D) for the explicit heat scheme.",
+	})
+
+	// TrajectorySteps is the distribution of step counts for projectile
+	// trajectories.
+	TrajectorySteps = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "sim_trajectory_steps",
+		Help:    "Number of steps computed for a projectile trajectory.",
+		Buckets: prometheus.ExponentialBuckets(10, 2, 12),
+	})
+)
+
+// Serve starts a blocking HTTP server exposing /metrics on addr. It is
+// meant to run on a separate port from the public API (see --metrics-addr
+// in each backend's main), so Prometheus can scrape without the public
+// listener exposing internals.
+func Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRequestsTotalCountsByKindAndStatus(t *testing.T) {
+	RequestsTotal.Reset()
+
+	RequestsTotal.WithLabelValues("heat", "success").Inc()
+	RequestsTotal.WithLabelValues("heat", "success").Inc()
+	RequestsTotal.WithLabelValues("projectile", "invalid").Inc()
+
+	if got := testutil.ToFloat64(RequestsTotal.WithLabelValues("heat", "success")); got != 2 {
+		t.Errorf("heat/success = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(RequestsTotal.WithLabelValues("projectile", "invalid")); got != 1 {
+		t.Errorf("projectile/invalid = %v, want 1", got)
+	}
+}
+
+func TestCFLRatioReportsLastObservedValue(t *testing.T) {
+	CFLRatio.Set(0.3)
+	CFLRatio.Set(0.71)
+
+	if got := testutil.ToFloat64(CFLRatio); got != 0.71 {
+		t.Errorf("CFLRatio = %v, want 0.71", got)
+	}
+}
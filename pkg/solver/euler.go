@@ -0,0 +1,24 @@
+package solver
+
+// Euler integrates an ODE state vector with forward (explicit) Euler. It is
+// kept around for comparison against RK4; it is only first-order accurate
+// and tends to drift for large step sizes.
+type Euler struct {
+	Deriv Derivative
+}
+
+// NewEuler builds a forward-Euler solver for the given derivative function.
+func NewEuler(deriv Derivative) *Euler {
+	return &Euler{Deriv: deriv}
+}
+
+func (s *Euler) Name() string { return "euler" }
+
+func (s *Euler) Step(t float64, state []float64, dt float64) []float64 {
+	k := s.Deriv(t, state)
+	next := make([]float64, len(state))
+	for i := range state {
+		next[i] = state[i] + dt*k[i]
+	}
+	return next
+}
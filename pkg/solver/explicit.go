@@ -0,0 +1,44 @@
+package solver
+
+// Boundary is a fixed (Dirichlet) temperature applied at a grid endpoint.
+type Boundary struct {
+	Left  float64
+	Right float64
+}
+
+// ExplicitFTCS integrates the 1D heat equation with the forward-time,
+// central-space (FTCS) explicit scheme. It is only conditionally stable:
+// the Courant number r = alpha*dt/dx^2 must satisfy r <= 0.5, see CFL.
+type ExplicitFTCS struct {
+	Alpha    float64
+	Dx       float64
+	Boundary Boundary
+}
+
+// NewExplicitFTCS builds an explicit FTCS solver for the given diffusivity
+// and grid spacing.
+func NewExplicitFTCS(alpha, dx float64, boundary Boundary) *ExplicitFTCS {
+	return &ExplicitFTCS{Alpha: alpha, Dx: dx, Boundary: boundary}
+}
+
+// CFL returns the Courant number for the given time step. The explicit
+// scheme is stable iff CFL(dt) <= 0.5.
+func (s *ExplicitFTCS) CFL(dt float64) float64 {
+	return s.Alpha * dt / (s.Dx * s.Dx)
+}
+
+func (s *ExplicitFTCS) Name() string { return "explicit-ftcs" }
+
+func (s *ExplicitFTCS) Step(_ float64, state []float64, dt float64) []float64 {
+	n := len(state)
+	r := s.CFL(dt)
+	next := make([]float64, n)
+
+	next[0] = s.Boundary.Left
+	next[n-1] = s.Boundary.Right
+	for i := 1; i < n-1; i++ {
+		next[i] = state[i] + r*(state[i+1]-2*state[i]+state[i-1])
+	}
+
+	return next
+}
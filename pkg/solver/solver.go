@@ -0,0 +1,20 @@
+// Package solver provides pluggable time-integration schemes shared by the
+// simulation backends (heat conduction, projectile motion, ...). Each scheme
+// implements Solver and advances a state vector by one time step; callers
+// are responsible for interpreting the vector (grid of temperatures, ODE
+// state, ...) and for choosing a dt that respects the scheme's stability
+// requirements.
+package solver
+
+// Derivative computes the time-derivative of state at time t. It is used by
+// ODE solvers such as RK4; state must not be mutated in place.
+type Derivative func(t float64, state []float64) []float64
+
+// Solver advances a simulation state by a single time step.
+type Solver interface {
+	// Step returns the state at t+dt given the state at t.
+	Step(t float64, state []float64, dt float64) []float64
+
+	// Name identifies the scheme, e.g. for logging or API responses.
+	Name() string
+}
@@ -0,0 +1,117 @@
+package solver
+
+import (
+	"math"
+	"testing"
+)
+
+// TestExplicitFTCSAgainstFourierSeries checks the explicit scheme against
+// the analytical solution for a rod with zero Dirichlet boundaries and a
+// single-mode sine initial condition, T(x,t) = sin(pi*x/L) * exp(-alpha*(pi/L)^2*t),
+// which is an eigenfunction of the heat operator and so decays without
+// changing shape.
+func TestExplicitFTCSAgainstFourierSeries(t *testing.T) {
+	const (
+		length = 1.0
+		alpha  = 1e-4
+		n      = 21
+		dx     = length / (n - 1)
+	)
+	dt := 0.4 * dx * dx / alpha // r = 0.4, within the r <= 0.5 stability limit
+
+	s := NewExplicitFTCS(alpha, dx, Boundary{Left: 0, Right: 0})
+	state := make([]float64, n)
+	for i := range state {
+		x := float64(i) * dx
+		state[i] = math.Sin(math.Pi * x / length)
+	}
+
+	totalTime := 50.0
+	steps := int(totalTime / dt)
+	time := 0.0
+	for i := 0; i < steps; i++ {
+		state = s.Step(time, state, dt)
+		time += dt
+	}
+
+	decay := math.Exp(-alpha * math.Pow(math.Pi/length, 2) * time)
+	for i, v := range state {
+		x := float64(i) * dx
+		want := math.Sin(math.Pi*x/length) * decay
+		if math.Abs(v-want) > 1e-3 {
+			t.Errorf("node %d: got %f, want %f", i, v, want)
+		}
+	}
+}
+
+// TestCrankNicolsonAgainstFourierSeries mirrors TestExplicitFTCSAgainstFourierSeries
+// but uses a Courant number above the explicit stability limit, which
+// Crank-Nicolson should still integrate accurately since it is
+// unconditionally stable.
+func TestCrankNicolsonAgainstFourierSeries(t *testing.T) {
+	const (
+		length = 1.0
+		alpha  = 1e-4
+		n      = 21
+		dx     = length / (n - 1)
+	)
+	dt := 2.0 * dx * dx / alpha // r = 2.0, unstable for the explicit scheme
+
+	s := NewCrankNicolson(alpha, dx, Boundary{Left: 0, Right: 0})
+	state := make([]float64, n)
+	for i := range state {
+		x := float64(i) * dx
+		state[i] = math.Sin(math.Pi * x / length)
+	}
+
+	totalTime := 50.0
+	steps := int(totalTime / dt)
+	time := 0.0
+	for i := 0; i < steps; i++ {
+		state = s.Step(time, state, dt)
+		time += dt
+	}
+
+	decay := math.Exp(-alpha * math.Pow(math.Pi/length, 2) * time)
+	for i, v := range state {
+		x := float64(i) * dx
+		want := math.Sin(math.Pi*x/length) * decay
+		if math.Abs(v-want) > 1e-2 {
+			t.Errorf("node %d: got %f, want %f", i, v, want)
+		}
+	}
+}
+
+// TestRK4AgainstDragFreeParabola checks RK4 against the closed-form
+// drag-free projectile trajectory x(t) = v0x*t, y(t) = h0 + v0y*t - g*t^2/2.
+func TestRK4AgainstDragFreeParabola(t *testing.T) {
+	const (
+		g   = 9.81
+		v0x = 20.0
+		v0y = 15.0
+		h0  = 0.0
+		dt  = 0.001
+	)
+
+	deriv := func(_ float64, state []float64) []float64 {
+		// state = [x, y, vx, vy]
+		return []float64{state[2], state[3], 0, -g}
+	}
+
+	s := NewRK4(deriv)
+	state := []float64{0, h0, v0x, v0y}
+	time := 0.0
+	for i := 0; i < 500; i++ {
+		state = s.Step(time, state, dt)
+		time += dt
+	}
+
+	wantX := v0x * time
+	wantY := h0 + v0y*time - 0.5*g*time*time
+	if math.Abs(state[0]-wantX) > 1e-6 {
+		t.Errorf("x = %f, want %f", state[0], wantX)
+	}
+	if math.Abs(state[1]-wantY) > 1e-6 {
+		t.Errorf("y = %f, want %f", state[1], wantY)
+	}
+}
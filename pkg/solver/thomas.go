@@ -0,0 +1,29 @@
+package solver
+
+// thomasSolve solves the tridiagonal system with sub-diagonal a, diagonal b,
+// super-diagonal c and right-hand side d, all of length n (a[0] and
+// c[n-1] are unused). It uses the Thomas algorithm (a specialization of
+// Gaussian elimination for tridiagonal matrices) and returns the solution
+// vector. The inputs are not mutated.
+func thomasSolve(a, b, c, d []float64) []float64 {
+	n := len(d)
+	cp := make([]float64, n)
+	dp := make([]float64, n)
+
+	cp[0] = c[0] / b[0]
+	dp[0] = d[0] / b[0]
+	for i := 1; i < n; i++ {
+		m := b[i] - a[i]*cp[i-1]
+		if i < n-1 {
+			cp[i] = c[i] / m
+		}
+		dp[i] = (d[i] - a[i]*dp[i-1]) / m
+	}
+
+	x := make([]float64, n)
+	x[n-1] = dp[n-1]
+	for i := n - 2; i >= 0; i-- {
+		x[i] = dp[i] - cp[i]*x[i+1]
+	}
+	return x
+}
@@ -0,0 +1,104 @@
+package tasks
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"os"
+)
+
+// GobCodec is a pure-Go ArtifactCodec: no cgo, no system libraries. It
+// gzip-compresses a stream of gob-encoded CheckpointState values, one per
+// checkpoint and one per artifact frame. It's the default build's codec
+// (see DefaultCodec) so pkg/tasks, lab02/backend and cmd/simctl run
+// without libhdf5 installed; build with -tags hdf5 for the production
+// HDF5Codec instead.
+type GobCodec struct{}
+
+func (GobCodec) WriteCheckpoint(path string, level int, state CheckpointState) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create checkpoint file: %w", err)
+	}
+	defer f.Close()
+
+	gw, err := gzip.NewWriterLevel(f, gobCodecLevel(level))
+	if err != nil {
+		return fmt.Errorf("gzip writer: %w", err)
+	}
+	if err := gob.NewEncoder(gw).Encode(state); err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+	return gw.Close()
+}
+
+func (GobCodec) ReadCheckpoint(path string) (CheckpointState, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return CheckpointState{}, fmt.Errorf("open checkpoint file: %w", err)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return CheckpointState{}, fmt.Errorf("gzip reader: %w", err)
+	}
+	defer gr.Close()
+
+	var state CheckpointState
+	if err := gob.NewDecoder(gr).Decode(&state); err != nil {
+		return CheckpointState{}, fmt.Errorf("decode checkpoint: %w", err)
+	}
+	return state, nil
+}
+
+func (GobCodec) NewArtifactWriter(path string, level int) (ArtifactWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create artifact file: %w", err)
+	}
+	gw, err := gzip.NewWriterLevel(f, gobCodecLevel(level))
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("gzip writer: %w", err)
+	}
+	return &gobArtifactWriter{path: path, f: f, gw: gw, enc: gob.NewEncoder(gw)}, nil
+}
+
+func gobCodecLevel(level int) int {
+	if level == 0 {
+		return gzip.DefaultCompression
+	}
+	return level
+}
+
+// gobArtifactWriter streams frames into a single gzip-compressed gob
+// stream as they're produced, so a long-running task never needs its
+// whole trajectory held in memory at once.
+type gobArtifactWriter struct {
+	path string
+	f    *os.File
+	gw   *gzip.Writer
+	enc  *gob.Encoder
+}
+
+func (w *gobArtifactWriter) WriteFrame(state CheckpointState) error {
+	if err := w.enc.Encode(state); err != nil {
+		return fmt.Errorf("encode frame: %w", err)
+	}
+	return nil
+}
+
+func (w *gobArtifactWriter) Close() error {
+	if err := w.gw.Close(); err != nil {
+		w.f.Close()
+		return fmt.Errorf("close gzip writer: %w", err)
+	}
+	return w.f.Close()
+}
+
+func (w *gobArtifactWriter) Abort() error {
+	w.gw.Close()
+	w.f.Close()
+	return os.Remove(w.path)
+}
@@ -0,0 +1,282 @@
+//go:build hdf5
+
+// This file is cgo and needs libhdf5's headers and library on the build
+// machine, so it's opt-in: build (or test) with `-tags hdf5` to include
+// it. Without the tag, hdf5_stub.go provides the same HDF5Codec type so
+// the rest of the tree still builds; it just errors if actually used. On
+// Debian/Ubuntu the native dependency is `apt-get install libhdf5-dev`.
+
+package tasks
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"gonum.org/v1/hdf5"
+)
+
+// HDF5Codec is the production ArtifactCodec: it writes each checkpoint or
+// artifact as an HDF5 file, then gzip-compresses the whole file to its
+// final ".h5.gz" path. HDF5 is written to a scratch temp file first since
+// the C library needs a real, seekable file to operate on.
+type HDF5Codec struct{}
+
+// DefaultCodec returns the codec lab02/backend and cmd/simctl wire into
+// their task store. This build was compiled with -tags hdf5, so it's the
+// production HDF5Codec.
+func DefaultCodec() ArtifactCodec { return HDF5Codec{} }
+
+func writeFrame(f *hdf5.File, group string, state CheckpointState) error {
+	g, err := f.CreateGroup(group)
+	if err != nil {
+		return fmt.Errorf("create group %q: %w", group, err)
+	}
+	defer g.Close()
+
+	if err := writeDataset(g, "field", state.Field); err != nil {
+		return err
+	}
+	if len(state.Shape) > 0 {
+		shape := make([]float64, len(state.Shape))
+		for i, d := range state.Shape {
+			shape[i] = float64(d)
+		}
+		if err := writeDataset(g, "shape", shape); err != nil {
+			return err
+		}
+	}
+	return writeDataset(g, "time", []float64{state.Time})
+}
+
+func writeDataset(g *hdf5.Group, name string, data []float64) error {
+	dims := []uint{uint(len(data))}
+	space, err := hdf5.CreateDataspaceSimple(dims, dims)
+	if err != nil {
+		return fmt.Errorf("dataspace %q: %w", name, err)
+	}
+	defer space.Close()
+
+	dset, err := g.CreateDataset(name, hdf5.T_NATIVE_DOUBLE, space)
+	if err != nil {
+		return fmt.Errorf("dataset %q: %w", name, err)
+	}
+	defer dset.Close()
+
+	if err := dset.Write(&data); err != nil {
+		return fmt.Errorf("write dataset %q: %w", name, err)
+	}
+	return nil
+}
+
+func readDataset(g *hdf5.Group, name string) ([]float64, error) {
+	dset, err := g.OpenDataset(name)
+	if err != nil {
+		return nil, fmt.Errorf("open dataset %q: %w", name, err)
+	}
+	defer dset.Close()
+
+	space := dset.Space()
+	defer space.Close()
+	dims, _, err := space.SimpleExtentDims()
+	if err != nil {
+		return nil, fmt.Errorf("dataset %q dims: %w", name, err)
+	}
+
+	n := uint(1)
+	for _, d := range dims {
+		n *= d
+	}
+	data := make([]float64, n)
+	if err := dset.Read(&data); err != nil {
+		return nil, fmt.Errorf("read dataset %q: %w", name, err)
+	}
+	return data, nil
+}
+
+func readFrame(f *hdf5.File, group string) (CheckpointState, error) {
+	g, err := f.OpenGroup(group)
+	if err != nil {
+		return CheckpointState{}, fmt.Errorf("open group %q: %w", group, err)
+	}
+	defer g.Close()
+
+	field, err := readDataset(g, "field")
+	if err != nil {
+		return CheckpointState{}, err
+	}
+	t, err := readDataset(g, "time")
+	if err != nil {
+		return CheckpointState{}, err
+	}
+
+	state := CheckpointState{Time: t[0], Field: field}
+	if shape, err := readDataset(g, "shape"); err == nil {
+		state.Shape = make([]int, len(shape))
+		for i, d := range shape {
+			state.Shape[i] = int(d)
+		}
+	}
+	return state, nil
+}
+
+func (HDF5Codec) WriteCheckpoint(path string, level int, state CheckpointState) error {
+	return withTempH5(path, level, func(f *hdf5.File) error {
+		return writeFrame(f, "/checkpoint", state)
+	})
+}
+
+func (HDF5Codec) ReadCheckpoint(path string) (CheckpointState, error) {
+	var state CheckpointState
+	err := withGunzippedH5(path, func(f *hdf5.File) error {
+		var err error
+		state, err = readFrame(f, "/checkpoint")
+		return err
+	})
+	return state, err
+}
+
+// hdf5ArtifactWriter streams frames into a scratch HDF5 file one at a
+// time; the file is only gzip-compressed to its final path on Close, so a
+// long-running task never holds its full trajectory in memory.
+type hdf5ArtifactWriter struct {
+	dstPath string
+	level   int
+	tmpPath string
+	f       *hdf5.File
+	n       int
+}
+
+func (HDF5Codec) NewArtifactWriter(path string, level int) (ArtifactWriter, error) {
+	tmp, err := os.CreateTemp("", "tasks-*.h5")
+	if err != nil {
+		return nil, fmt.Errorf("create scratch file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+
+	f, err := hdf5.CreateFile(tmpPath, hdf5.F_ACC_TRUNC)
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("create hdf5 file: %w", err)
+	}
+	return &hdf5ArtifactWriter{dstPath: path, level: level, tmpPath: tmpPath, f: f}, nil
+}
+
+func (w *hdf5ArtifactWriter) WriteFrame(state CheckpointState) error {
+	err := writeFrame(w.f, fmt.Sprintf("/frames/%06d", w.n), state)
+	w.n++
+	return err
+}
+
+func (w *hdf5ArtifactWriter) Close() error {
+	defer os.Remove(w.tmpPath)
+	if err := w.f.Close(); err != nil {
+		return fmt.Errorf("close hdf5 file: %w", err)
+	}
+	return gzipFile(w.tmpPath, w.dstPath, w.level)
+}
+
+func (w *hdf5ArtifactWriter) Abort() error {
+	defer os.Remove(w.tmpPath)
+	return w.f.Close()
+}
+
+// withTempH5 creates a scratch HDF5 file, lets fn populate it, then
+// gzip-compresses it to path at the given compression level (0 uses the
+// default).
+func withTempH5(path string, level int, fn func(f *hdf5.File) error) error {
+	tmp, err := os.CreateTemp("", "tasks-*.h5")
+	if err != nil {
+		return fmt.Errorf("create scratch file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath)
+
+	f, err := hdf5.CreateFile(tmpPath, hdf5.F_ACC_TRUNC)
+	if err != nil {
+		return fmt.Errorf("create hdf5 file: %w", err)
+	}
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close hdf5 file: %w", err)
+	}
+
+	return gzipFile(tmpPath, path, level)
+}
+
+// withGunzippedH5 decompresses path to a scratch file and opens it as
+// HDF5 for fn to read from.
+func withGunzippedH5(path string, fn func(f *hdf5.File) error) error {
+	tmpPath, err := gunzipToTemp(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	f, err := hdf5.OpenFile(tmpPath, hdf5.F_ACC_RDONLY)
+	if err != nil {
+		return fmt.Errorf("open hdf5 file: %w", err)
+	}
+	defer f.Close()
+
+	return fn(f)
+}
+
+func gzipFile(srcPath, dstPath string, level int) error {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	gw, err := gzip.NewWriterLevel(dst, level)
+	if err != nil {
+		return fmt.Errorf("gzip writer: %w", err)
+	}
+	if _, err := io.Copy(gw, src); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func gunzipToTemp(path string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	gr, err := gzip.NewReader(src)
+	if err != nil {
+		return "", fmt.Errorf("gzip reader: %w", err)
+	}
+	defer gr.Close()
+
+	tmp, err := os.CreateTemp("", "tasks-*.h5")
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+
+	if _, err := io.Copy(tmp, gr); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
@@ -0,0 +1,279 @@
+// Package tasks persists long-running simulations as resumable units of
+// work: parameters, status, and periodic checkpoints survive process
+// restarts and dropped connections, instead of living only in a
+// websocket handler's memory.
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status is the lifecycle state of a task.
+type Status string
+
+const (
+	StatusQueued  Status = "queued"
+	StatusRunning Status = "running"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed"
+)
+
+// Task is the persisted record for one simulation run.
+type Task struct {
+	ID     string          `json:"id"`
+	Kind   string          `json:"kind"` // "heat" or "projectile"
+	Params json.RawMessage `json:"params"`
+	Status Status          `json:"status"`
+	Error  string          `json:"error,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Steps is the number of integration steps checkpointed so far.
+	Steps int `json:"steps"`
+
+	CheckpointEvery  int `json:"checkpointEvery"`  // checkpoint cadence, in steps
+	CompressionLevel int `json:"compressionLevel"` // gzip level, compress/gzip constants
+}
+
+// CheckpointState is the minimal state needed to resume a simulation: the
+// field (temperatures, or an ODE state vector) at a point in simulated
+// time, plus its grid shape if any.
+type CheckpointState struct {
+	Time  float64   `json:"time"`
+	Shape []int     `json:"shape,omitempty"` // e.g. [nx, ny]; omitted for 1D/ODE state
+	Field []float64 `json:"field"`
+}
+
+// ArtifactCodec persists and loads simulation state to/from disk. The
+// production implementation (HDF5Codec) writes gzip-compressed HDF5; tests
+// use a fake so they don't depend on a real HDF5 install.
+type ArtifactCodec interface {
+	WriteCheckpoint(path string, level int, state CheckpointState) error
+	ReadCheckpoint(path string) (CheckpointState, error)
+
+	// NewArtifactWriter opens a streaming writer for a task's full result.
+	// Frames are written one at a time as the simulation produces them, so
+	// a long run never needs its whole trajectory held in memory at once.
+	NewArtifactWriter(path string, level int) (ArtifactWriter, error)
+}
+
+// ArtifactWriter streams frames to a single artifact file. Close finalizes
+// the artifact at its destination path; Abort discards whatever was
+// written so far (for a task that was stopped before completion).
+type ArtifactWriter interface {
+	WriteFrame(state CheckpointState) error
+	Close() error
+	Abort() error
+}
+
+// Store manages tasks under a base directory, one subdirectory per task:
+//
+//	<dir>/<id>/meta.json        task metadata
+//	<dir>/<id>/checkpoint.h5.gz latest checkpoint (for resume)
+//	<dir>/<id>/artifact.h5.gz   full result, written once the task is done
+type Store struct {
+	dir   string
+	codec ArtifactCodec
+
+	mu    sync.Mutex
+	tasks map[string]*Task
+}
+
+// NewStore opens (or creates) a task store rooted at dir, loading any
+// tasks already persisted there from a previous process.
+func NewStore(dir string, codec ArtifactCodec) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("tasks: create store dir: %w", err)
+	}
+
+	s := &Store{dir: dir, codec: codec, tasks: map[string]*Task{}}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("tasks: read store dir: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name(), "meta.json"))
+		if err != nil {
+			continue // not a task directory
+		}
+		var t Task
+		if err := json.Unmarshal(data, &t); err != nil {
+			continue
+		}
+		s.tasks[t.ID] = &t
+	}
+
+	return s, nil
+}
+
+func (s *Store) taskDir(id string) string      { return filepath.Join(s.dir, id) }
+func (s *Store) metaPath(id string) string     { return filepath.Join(s.taskDir(id), "meta.json") }
+func (s *Store) ArtifactPath(id string) string { return filepath.Join(s.taskDir(id), "artifact.h5.gz") }
+func (s *Store) CheckpointPath(id string) string {
+	return filepath.Join(s.taskDir(id), "checkpoint.h5.gz")
+}
+
+// Create registers a new queued task and persists its metadata.
+func (s *Store) Create(kind string, params json.RawMessage, checkpointEvery, compressionLevel int) (*Task, error) {
+	now := time.Now()
+	t := &Task{
+		ID:               uuid.NewString(),
+		Kind:             kind,
+		Params:           params,
+		Status:           StatusQueued,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		CheckpointEvery:  checkpointEvery,
+		CompressionLevel: compressionLevel,
+	}
+
+	if err := os.MkdirAll(s.taskDir(t.ID), 0o755); err != nil {
+		return nil, fmt.Errorf("tasks: create task dir: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[t.ID] = t
+	if err := s.persist(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// persist writes t's metadata to disk. Callers must hold s.mu.
+func (s *Store) persist(t *Task) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("tasks: marshal metadata: %w", err)
+	}
+	if err := os.WriteFile(s.metaPath(t.ID), data, 0o644); err != nil {
+		return fmt.Errorf("tasks: write metadata: %w", err)
+	}
+	return nil
+}
+
+// Get returns a copy of the task with the given ID.
+func (s *Store) Get(id string) (Task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[id]
+	if !ok {
+		return Task{}, false
+	}
+	return *t, true
+}
+
+// List returns a copy of every known task.
+func (s *Store) List() []Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		out = append(out, *t)
+	}
+	return out
+}
+
+// Delete removes a task's metadata and artifacts.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[id]; !ok {
+		return fmt.Errorf("tasks: unknown task %q", id)
+	}
+	delete(s.tasks, id)
+	return os.RemoveAll(s.taskDir(id))
+}
+
+// SetStatus updates a task's status (and, for StatusFailed, its error
+// message), persisting the change.
+func (s *Store) SetStatus(id string, status Status, errMsg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[id]
+	if !ok {
+		return fmt.Errorf("tasks: unknown task %q", id)
+	}
+	t.Status = status
+	t.Error = errMsg
+	t.UpdatedAt = time.Now()
+	return s.persist(t)
+}
+
+// SaveCheckpoint writes state as the task's latest checkpoint and bumps
+// its recorded step count, so a future Resume can pick up from here.
+func (s *Store) SaveCheckpoint(id string, steps int, state CheckpointState) error {
+	s.mu.Lock()
+	t, ok := s.tasks[id]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("tasks: unknown task %q", id)
+	}
+	level := t.CompressionLevel
+	s.mu.Unlock()
+
+	if err := s.codec.WriteCheckpoint(s.CheckpointPath(id), level, state); err != nil {
+		return fmt.Errorf("tasks: write checkpoint: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.Steps = steps
+	t.UpdatedAt = time.Now()
+	return s.persist(t)
+}
+
+// LoadCheckpoint reads the task's latest checkpoint, for resuming a run.
+func (s *Store) LoadCheckpoint(id string) (CheckpointState, error) {
+	if _, ok := s.Get(id); !ok {
+		return CheckpointState{}, fmt.Errorf("tasks: unknown task %q", id)
+	}
+	return s.codec.ReadCheckpoint(s.CheckpointPath(id))
+}
+
+// HasCheckpoint reports whether a task has a checkpoint to resume from.
+// This is tracked on the task itself (Steps > 0 once SaveCheckpoint has
+// run) rather than by statting the checkpoint file, since codecs such as
+// the test fake don't necessarily write one to disk.
+func (s *Store) HasCheckpoint(id string) bool {
+	t, ok := s.Get(id)
+	return ok && t.Steps > 0
+}
+
+// OpenArtifact opens a streaming writer for id's result artifact. The
+// caller writes frames as they're computed, then calls Close (success) or
+// Abort (the task was stopped early) when done.
+func (s *Store) OpenArtifact(id string) (ArtifactWriter, error) {
+	s.mu.Lock()
+	t, ok := s.tasks[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("tasks: unknown task %q", id)
+	}
+	level := t.CompressionLevel
+	s.mu.Unlock()
+
+	w, err := s.codec.NewArtifactWriter(s.ArtifactPath(id), level)
+	if err != nil {
+		return nil, fmt.Errorf("tasks: open artifact: %w", err)
+	}
+	return w, nil
+}
+
+// FinishArtifact marks a task done after its artifact writer has been
+// closed successfully.
+func (s *Store) FinishArtifact(id string) error {
+	return s.SetStatus(id, StatusDone, "")
+}
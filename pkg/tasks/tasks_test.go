@@ -0,0 +1,229 @@
+package tasks
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// fakeCodec is an in-memory ArtifactCodec so these tests don't depend on a
+// real HDF5 install.
+type fakeCodec struct {
+	checkpoints map[string]CheckpointState
+	artifacts   map[string][]CheckpointState
+}
+
+func newFakeCodec() *fakeCodec {
+	return &fakeCodec{
+		checkpoints: map[string]CheckpointState{},
+		artifacts:   map[string][]CheckpointState{},
+	}
+}
+
+func (f *fakeCodec) WriteCheckpoint(path string, level int, state CheckpointState) error {
+	f.checkpoints[path] = state
+	return nil
+}
+
+func (f *fakeCodec) ReadCheckpoint(path string) (CheckpointState, error) {
+	return f.checkpoints[path], nil
+}
+
+func (f *fakeCodec) NewArtifactWriter(path string, level int) (ArtifactWriter, error) {
+	return &fakeArtifactWriter{codec: f, path: path}, nil
+}
+
+// fakeArtifactWriter buffers frames in memory, which is fine for a test
+// but is exactly what the real HDF5Codec avoids for long runs.
+type fakeArtifactWriter struct {
+	codec  *fakeCodec
+	path   string
+	frames []CheckpointState
+}
+
+func (w *fakeArtifactWriter) WriteFrame(state CheckpointState) error {
+	w.frames = append(w.frames, state)
+	return nil
+}
+
+func (w *fakeArtifactWriter) Close() error {
+	w.codec.artifacts[w.path] = w.frames
+	return nil
+}
+
+func (w *fakeArtifactWriter) Abort() error { return nil }
+
+func TestCreateGetList(t *testing.T) {
+	store, err := NewStore(t.TempDir(), newFakeCodec())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	task, err := store.Create("heat", json.RawMessage(`{"length":1}`), 10, 6)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if task.Status != StatusQueued {
+		t.Errorf("Status = %q, want queued", task.Status)
+	}
+
+	got, ok := store.Get(task.ID)
+	if !ok {
+		t.Fatalf("Get(%s) not found", task.ID)
+	}
+	if got.Kind != "heat" {
+		t.Errorf("Kind = %q, want heat", got.Kind)
+	}
+
+	if len(store.List()) != 1 {
+		t.Errorf("List() has %d tasks, want 1", len(store.List()))
+	}
+}
+
+func TestSetStatus(t *testing.T) {
+	store, _ := NewStore(t.TempDir(), newFakeCodec())
+	task, _ := store.Create("projectile", nil, 0, 0)
+
+	if err := store.SetStatus(task.ID, StatusFailed, "boom"); err != nil {
+		t.Fatalf("SetStatus: %v", err)
+	}
+
+	got, _ := store.Get(task.ID)
+	if got.Status != StatusFailed || got.Error != "boom" {
+		t.Errorf("got status=%q error=%q, want failed/boom", got.Status, got.Error)
+	}
+}
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	store, _ := NewStore(t.TempDir(), newFakeCodec())
+	task, _ := store.Create("heat", nil, 10, 6)
+
+	state := CheckpointState{Time: 1.5, Shape: []int{3, 2}, Field: []float64{1, 2, 3, 4, 5, 6}}
+	if err := store.SaveCheckpoint(task.ID, 50, state); err != nil {
+		t.Fatalf("SaveCheckpoint: %v", err)
+	}
+
+	got, _ := store.Get(task.ID)
+	if got.Steps != 50 {
+		t.Errorf("Steps = %d, want 50", got.Steps)
+	}
+	if !store.HasCheckpoint(task.ID) {
+		t.Error("HasCheckpoint = false, want true")
+	}
+
+	loaded, err := store.LoadCheckpoint(task.ID)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint: %v", err)
+	}
+	if loaded.Time != state.Time || len(loaded.Field) != len(state.Field) {
+		t.Errorf("LoadCheckpoint = %+v, want %+v", loaded, state)
+	}
+}
+
+func TestSaveArtifactMarksDone(t *testing.T) {
+	store, _ := NewStore(t.TempDir(), newFakeCodec())
+	task, _ := store.Create("heat", nil, 10, 6)
+
+	writer, err := store.OpenArtifact(task.ID)
+	if err != nil {
+		t.Fatalf("OpenArtifact: %v", err)
+	}
+	for _, frame := range []CheckpointState{{Time: 0, Field: []float64{1}}, {Time: 1, Field: []float64{2}}} {
+		if err := writer.WriteFrame(frame); err != nil {
+			t.Fatalf("WriteFrame: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := store.FinishArtifact(task.ID); err != nil {
+		t.Fatalf("FinishArtifact: %v", err)
+	}
+
+	got, _ := store.Get(task.ID)
+	if got.Status != StatusDone {
+		t.Errorf("Status = %q, want done", got.Status)
+	}
+}
+
+func TestAbortedArtifactLeavesTaskUnfinished(t *testing.T) {
+	store, _ := NewStore(t.TempDir(), newFakeCodec())
+	task, _ := store.Create("heat", nil, 10, 6)
+
+	writer, err := store.OpenArtifact(task.ID)
+	if err != nil {
+		t.Fatalf("OpenArtifact: %v", err)
+	}
+	writer.WriteFrame(CheckpointState{Time: 0, Field: []float64{1}})
+	if err := writer.Abort(); err != nil {
+		t.Fatalf("Abort: %v", err)
+	}
+
+	got, _ := store.Get(task.ID)
+	if got.Status == StatusDone {
+		t.Error("Status = done, want unfinished after Abort")
+	}
+}
+
+func TestDeleteRemovesTask(t *testing.T) {
+	store, _ := NewStore(t.TempDir(), newFakeCodec())
+	task, _ := store.Create("heat", nil, 10, 6)
+
+	if err := store.Delete(task.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := store.Get(task.ID); ok {
+		t.Error("Get found deleted task")
+	}
+}
+
+func TestGobCodecRoundTrip(t *testing.T) {
+	store, err := NewStore(t.TempDir(), GobCodec{})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	task, _ := store.Create("heat", nil, 10, 6)
+
+	state := CheckpointState{Time: 1.5, Shape: []int{3, 2}, Field: []float64{1, 2, 3, 4, 5, 6}}
+	if err := store.SaveCheckpoint(task.ID, 50, state); err != nil {
+		t.Fatalf("SaveCheckpoint: %v", err)
+	}
+	loaded, err := store.LoadCheckpoint(task.ID)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint: %v", err)
+	}
+	if loaded.Time != state.Time || len(loaded.Field) != len(state.Field) {
+		t.Errorf("LoadCheckpoint = %+v, want %+v", loaded, state)
+	}
+
+	writer, err := store.OpenArtifact(task.ID)
+	if err != nil {
+		t.Fatalf("OpenArtifact: %v", err)
+	}
+	frames := []CheckpointState{{Time: 0, Field: []float64{1}}, {Time: 1, Field: []float64{2}}}
+	for _, frame := range frames {
+		if err := writer.WriteFrame(frame); err != nil {
+			t.Fatalf("WriteFrame: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestNewStoreReloadsPersistedTasks(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewStore(dir, newFakeCodec())
+	task, _ := store.Create("heat", json.RawMessage(`{"alpha":1e-4}`), 10, 6)
+
+	reopened, err := NewStore(dir, newFakeCodec())
+	if err != nil {
+		t.Fatalf("NewStore (reopen): %v", err)
+	}
+	got, ok := reopened.Get(task.ID)
+	if !ok {
+		t.Fatalf("task %s not reloaded", task.ID)
+	}
+	if got.Kind != "heat" {
+		t.Errorf("Kind = %q, want heat", got.Kind)
+	}
+}